@@ -0,0 +1,206 @@
+// Package injecttag defines an analyzer which reports inject tags and InjectFunc values
+// that injectgo rejects at runtime.
+package injecttag
+
+import (
+	"go/ast"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const (
+	injectTag     = "inject"
+	privateTag    = "private"
+	injectgoPath  = "github.com/RivenZoo/injectgo"
+	injectFuncObj = "InjectFunc"
+)
+
+const doc = `check injectgo inject tags and InjectFunc values
+
+The injecttag analyzer reports:
+  - inject tags on fields which are not pointer or interface
+  - inject tags on unexported fields
+  - private inject tags on fields which are not pointer to struct
+  - malformed inject tags
+  - InjectFunc whose Fn is not func() T or func() (T, error)
+  - InjectFunc whose Receiver is not *T where Fn returns T`
+
+// Analyzer reports invalid inject tags and InjectFunc values.
+var Analyzer = &analysis.Analyzer{
+	Name:     "injecttag",
+	Doc:      doc,
+	Requires: []*analysis.Analyzer{inspect.Analyzer},
+	Run:      run,
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	nodeFilter := []ast.Node{
+		(*ast.StructType)(nil),
+		(*ast.CompositeLit)(nil),
+	}
+	insp.Preorder(nodeFilter, func(n ast.Node) {
+		switch n := n.(type) {
+		case *ast.StructType:
+			checkStruct(pass, n)
+		case *ast.CompositeLit:
+			if isInjectFunc(pass.TypesInfo.TypeOf(n)) {
+				checkInjectFunc(pass, n)
+			}
+		}
+	})
+	return nil, nil
+}
+
+func checkStruct(pass *analysis.Pass, st *ast.StructType) {
+	for _, field := range st.Fields.List {
+		if field.Tag == nil {
+			continue
+		}
+		raw, err := strconv.Unquote(field.Tag.Value)
+		if err != nil {
+			continue
+		}
+		name, ok := reflect.StructTag(raw).Lookup(injectTag)
+		if !ok {
+			if strings.Contains(raw, injectTag+":") {
+				pass.Reportf(field.Tag.Pos(), "malformed inject tag %s, should be like `inject:\"\"` or `inject:\"name\"`", field.Tag.Value)
+			}
+			continue
+		}
+		if strings.ContainsAny(name, ", \t") {
+			pass.Reportf(field.Tag.Pos(), "inject tag name %q should not contain options or spaces", name)
+		}
+
+		tp := pass.TypesInfo.TypeOf(field.Type)
+		if tp == nil {
+			continue
+		}
+		switch tp.Underlying().(type) {
+		case *types.Pointer, *types.Interface:
+		default:
+			pass.Reportf(field.Type.Pos(), "inject field type %s should be pointer or interface", tp)
+		}
+		if name == privateTag && !isStructPointer(tp) {
+			pass.Reportf(field.Type.Pos(), "private inject field type %s should be pointer to struct", tp)
+		}
+
+		for _, ident := range field.Names {
+			if !ident.IsExported() {
+				pass.Reportf(ident.Pos(), "inject field %s should be exported", ident.Name)
+			}
+		}
+		if len(field.Names) == 0 && !isExportedEmbedded(tp) {
+			pass.Reportf(field.Type.Pos(), "embedded inject field %s should be exported", tp)
+		}
+	}
+}
+
+func isStructPointer(tp types.Type) bool {
+	p, ok := tp.Underlying().(*types.Pointer)
+	if !ok {
+		return false
+	}
+	_, ok = p.Elem().Underlying().(*types.Struct)
+	return ok
+}
+
+func isExportedEmbedded(tp types.Type) bool {
+	if p, ok := tp.(*types.Pointer); ok {
+		tp = p.Elem()
+	}
+	if named, ok := tp.(*types.Named); ok {
+		return named.Obj().Exported()
+	}
+	return true
+}
+
+func isInjectFunc(tp types.Type) bool {
+	named, ok := tp.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == injectgoPath && obj.Name() == injectFuncObj
+}
+
+// checkInjectFunc check Fn and Receiver of InjectFunc literal. Missing Fn is reported only for keyed literal
+// setting other fields, zero literal may be filled later.
+func checkInjectFunc(pass *analysis.Pass, lit *ast.CompositeLit) {
+	if len(lit.Elts) == 0 {
+		return
+	}
+	st, _ := pass.TypesInfo.TypeOf(lit).Underlying().(*types.Struct)
+	var fn, receiver ast.Expr
+	keyed := false
+	for i, elt := range lit.Elts {
+		name, value := "", elt
+		if kv, ok := elt.(*ast.KeyValueExpr); ok {
+			keyed = true
+			if key, ok := kv.Key.(*ast.Ident); ok {
+				name, value = key.Name, kv.Value
+			}
+		} else if st != nil && i < st.NumFields() {
+			name = st.Field(i).Name()
+		}
+		switch name {
+		case "Fn":
+			fn = value
+		case "Receiver":
+			receiver = value
+		}
+	}
+	if fn == nil {
+		if keyed {
+			pass.Reportf(lit.Pos(), "InjectFunc should set Fn")
+		}
+		return
+	}
+
+	ret := checkFn(pass, fn)
+	if ret == nil || receiver == nil {
+		return
+	}
+	rtp := pass.TypesInfo.TypeOf(receiver)
+	if rtp == nil || types.Identical(rtp, types.Typ[types.UntypedNil]) {
+		return
+	}
+	ptr, ok := rtp.Underlying().(*types.Pointer)
+	if !ok || !types.AssignableTo(ret, ptr.Elem()) {
+		pass.Reportf(receiver.Pos(), "InjectFunc Receiver %s should be *%s", rtp, ret)
+	}
+}
+
+// checkFn report Fn which is not func() T / func() (T, error), return T if Fn is valid.
+func checkFn(pass *analysis.Pass, fn ast.Expr) types.Type {
+	tp := pass.TypesInfo.TypeOf(fn)
+	if tp == nil {
+		return nil
+	}
+	sig, ok := tp.Underlying().(*types.Signature)
+	if !ok {
+		pass.Reportf(fn.Pos(), "InjectFunc Fn %s should be function", tp)
+		return nil
+	}
+	if sig.Params().Len() != 0 || sig.Variadic() {
+		pass.Reportf(fn.Pos(), "InjectFunc Fn %s should not accept arguments", tp)
+		return nil
+	}
+	results := sig.Results()
+	if results.Len() == 0 || results.Len() > 2 {
+		pass.Reportf(fn.Pos(), "InjectFunc Fn %s should return T or (T, error)", tp)
+		return nil
+	}
+	if results.Len() == 2 && !types.Identical(results.At(1).Type(), types.Universe.Lookup("error").Type()) {
+		pass.Reportf(fn.Pos(), "InjectFunc Fn %s second return value should be error", tp)
+		return nil
+	}
+	return results.At(0).Type()
+}
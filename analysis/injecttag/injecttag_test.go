@@ -0,0 +1,12 @@
+package injecttag_test
+
+import (
+	"testing"
+
+	"github.com/RivenZoo/injectgo/analysis/injecttag"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), injecttag.Analyzer, "a")
+}
@@ -0,0 +1,57 @@
+package a
+
+import (
+	"fmt"
+
+	"github.com/RivenZoo/injectgo"
+)
+
+type Client struct{}
+
+func (c *Client) String() string {
+	return "client"
+}
+
+type Valid struct {
+	Client   *Client      `inject:""`
+	Named    *Client      `inject:"client"`
+	Stringer fmt.Stringer `inject:""`
+	Private  *Client      `inject:"private"`
+	Name     string
+}
+
+type Invalid struct {
+	Name      string       `inject:""`            // want `inject field type string should be pointer or interface`
+	Client    Client       `inject:""`            // want `inject field type a.Client should be pointer or interface`
+	client    *Client      `inject:""`            // want `inject field client should be exported`
+	Option    *Client      `inject:"client,omit"` // want `inject tag name "client,omit" should not contain options or spaces`
+	Quoteless *Client      `inject:client`        // want "malformed inject tag"
+	Private   fmt.Stringer `inject:"private"`     // want `private inject field type fmt.Stringer should be pointer to struct`
+}
+
+func NewClient() *Client {
+	return &Client{}
+}
+
+func NewClientErr() (*Client, error) {
+	return &Client{}, nil
+}
+
+func funcs() {
+	var cli *Client
+	var stringer fmt.Stringer
+	_ = injectgo.InjectFunc{Fn: NewClient, Receiver: &cli}
+	_ = injectgo.InjectFunc{Fn: NewClientErr}
+	_ = injectgo.InjectFunc{Fn: func() *Client { return &Client{} }, Receiver: &stringer}
+	_ = injectgo.InjectFunc{NewClient, "", &cli}
+	_ = injectgo.InjectFunc{}
+
+	_ = injectgo.InjectFunc{Fn: "NewClient"}                                 // want `InjectFunc Fn string should be function`
+	_ = injectgo.InjectFunc{Fn: func(s string) *Client { return nil }}       // want `should not accept arguments`
+	_ = injectgo.InjectFunc{Fn: func() {}}                                   // want `should return T or \(T, error\)`
+	_ = injectgo.InjectFunc{Fn: func() (*Client, string) { return nil, "" }} // want `second return value should be error`
+	_ = injectgo.InjectFunc{Fn: NewClient, Receiver: cli}                    // want `InjectFunc Receiver \*a.Client should be \*\*a.Client`
+	_ = injectgo.InjectFunc{Label: "no fn"}                                  // want `InjectFunc should set Fn`
+	_ = injectgo.InjectFunc{"NewClient", "", nil}                            // want `InjectFunc Fn string should be function`
+	_ = injectgo.InjectFunc{NewClient, "", cli}                              // want `InjectFunc Receiver \*a.Client should be \*\*a.Client`
+}
@@ -0,0 +1,7 @@
+package injectgo
+
+type InjectFunc struct {
+	Fn       interface{}
+	Label    string
+	Receiver interface{}
+}
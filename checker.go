@@ -13,6 +13,8 @@ type injectChecker struct {
 
 	namedValues   map[string]reflect.Value
 	unnamedValues map[reflect.Type]reflect.Value
+
+	privateTypes map[reflect.Type]bool // types of private fields whose fields are pushed
 }
 
 func newInjectChecker() *injectChecker {
@@ -22,6 +24,7 @@ func newInjectChecker() *injectChecker {
 		unfulfilledNamedValues:       make(map[string]reflect.Value),
 		namedValues:                  make(map[string]reflect.Value),
 		unnamedValues:                make(map[reflect.Type]reflect.Value),
+		privateTypes:                 make(map[reflect.Type]bool),
 	}
 }
 
@@ -34,6 +37,10 @@ func (c *injectChecker) pushInjectedFields(obj reflect.Value) {
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
 		if inj, ok := field.Tag.Lookup(injectTag); ok {
+			if inj == privateTag {
+				c.pushPrivateField(field, obj)
+				continue
+			}
 			if inj != "" {
 				if _, ok := c.unfulfilledNamedValues[inj]; !ok {
 					c.unfulfilledNamedValues[inj] = obj
@@ -57,6 +64,18 @@ func (c *injectChecker) pushInjectedFields(obj reflect.Value) {
 	}
 }
 
+// pushPrivateField push fields of object created for private field, which fulfills nothing.
+func (c *injectChecker) pushPrivateField(field reflect.StructField, obj reflect.Value) {
+	if field.Type.Kind() != reflect.Ptr || field.Type.Elem().Kind() != reflect.Struct {
+		panic(fmt.Errorf("private field %v of object %v should be pointer to struct", field, obj))
+	}
+	if c.privateTypes[field.Type] {
+		return
+	}
+	c.privateTypes[field.Type] = true
+	c.pushInjectedFields(reflect.New(field.Type.Elem()))
+}
+
 func (c *injectChecker) popFulfilledUnnamedValues(obj reflect.Value) {
 	t := obj.Type()
 
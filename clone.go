@@ -0,0 +1,93 @@
+package injectgo
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Clone return a new container with copies of all provided objects, functions, names, labels, decorators and fallbacks.
+// Each provided object is shallow copied, so populating either container does not affect the other,
+// copy of a provided object is returned by CopyOf of the new container.
+// Functions are shared and called by each populated container.
+// Receiver of a function is rebound to a new variable of the new container, so it is not shared by clones,
+// the variable is returned by CopyOf with the original Receiver.
+// It panics if c is already populated.
+func (c *Container) Clone() *Container {
+	c.rlockPopulated()
+	defer c.mu.RUnlock()
+	if c.populated {
+		panic(fmt.Errorf("clone populated container"))
+	}
+
+	n := NewContainer()
+	n.parent = c.parent
+	n.graph.parent = c.graph.parent
+	copies := make(map[uintptr]reflect.Value)
+	for i := range c.unnamedValues {
+		pv := c.unnamedValues[i]
+		n.unnamedValues = append(n.unnamedValues, providedValue{value: copyObject(pv.value, copies), label: pv.label, site: pv.site, module: pv.module, private: pv.private})
+	}
+	for name, pv := range c.namedValues {
+		n.namedValues[name] = providedValue{value: copyObject(pv.value, copies), label: pv.label, site: pv.site, module: pv.module, private: pv.private}
+	}
+	for i := range c.unnamedFunctions {
+		n.unnamedFunctions = append(n.unnamedFunctions, cloneFunction(c.unnamedFunctions[i], copies))
+	}
+	for _, name := range c.namedFuncOrder {
+		n.namedFunctions[name] = cloneFunction(c.namedFunctions[name], copies)
+		n.namedFuncOrder = append(n.namedFuncOrder, name)
+	}
+	for name, m := range c.modules {
+		n.modules[name] = m
+	}
+	n.funcOrder = c.funcOrder
+	n.decorators = append(n.decorators, c.decorators...)
+	n.fallbacks = append(n.fallbacks, c.fallbacks...)
+	// copies of objects c copied from others are also copies of the originals
+	n.copies = copies
+	for orig, cp := range c.copies {
+		if ncp, ok := copies[cp.Pointer()]; ok {
+			n.copies[orig] = ncp
+		}
+	}
+	return n
+}
+
+// cloneFunction return copy of ifn whose Receiver is copied and recorded in copies.
+func cloneFunction(ifn InjectFunc, copies map[uintptr]reflect.Value) InjectFunc {
+	n := ifn.clone()
+	if ifn.Receiver != nil {
+		n.Receiver = copyObject(reflect.ValueOf(ifn.Receiver), copies).Interface()
+	}
+	return n
+}
+
+// CopyOf return copy of obj in container created by Clone, false if obj is not copied.
+func (c *Container) CopyOf(obj interface{}) (interface{}, bool) {
+	v := reflect.ValueOf(obj)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return nil, false
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	cp, ok := c.copies[v.Pointer()]
+	if !ok {
+		return nil, false
+	}
+	return cp.Interface(), true
+}
+
+// copyObject return a new pointer to a shallow copy of struct v points to.
+// Same pointer is copied once and recorded in copies.
+func copyObject(v reflect.Value, copies map[uintptr]reflect.Value) reflect.Value {
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return v
+	}
+	if cp, ok := copies[v.Pointer()]; ok {
+		return cp
+	}
+	cp := reflect.New(v.Type().Elem())
+	cp.Elem().Set(v.Elem())
+	copies[v.Pointer()] = cp
+	return cp
+}
@@ -0,0 +1,78 @@
+package injectgo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainer_Clone(t *testing.T) {
+	type B struct {
+		Name string
+	}
+
+	type A struct {
+		B     *B `inject:""`
+		Named *B `inject:"NameB"`
+	}
+
+	a := &A{}
+	b := &B{"b"}
+	base := NewContainer()
+	base.Provide(a, b)
+	base.ProvideByNameWithLabel("NameB", "prod", b)
+	base.ProvideFuncByName("NameB2", InjectFunc{
+		Fn: func() *B { return &B{"b2"} },
+	})
+
+	c1 := base.Clone()
+	c1.Populate(nil)
+
+	c2 := base.Clone()
+	assert.NoError(t, c2.ReplaceType(&B{"fake b"}))
+	c2.Populate(nil)
+
+	assert.Nil(t, a.B, "base object should not be populated")
+	assert.Nil(t, a.Named)
+
+	obj, ok := c1.CopyOf(a)
+	assert.True(t, ok)
+	a1 := obj.(*A)
+	assert.Equal(t, "b", a1.B.Name)
+	assert.True(t, a1.B == a1.Named, "same object should be copied once")
+	assert.False(t, a1.B == b)
+
+	obj, ok = c2.CopyOf(a)
+	assert.True(t, ok)
+	a2 := obj.(*A)
+	assert.Equal(t, "fake b", a2.B.Name)
+	assert.Equal(t, "b", a2.Named.Name)
+	assert.False(t, a1 == a2)
+
+	c3 := base.Clone()
+	a3, _ := c3.CopyOf(a)
+	obj, ok = c3.Clone().CopyOf(a)
+	assert.True(t, ok, "copy of copy should be copy of original")
+	assert.False(t, obj == a3)
+	_, ok = c3.CopyOf(&B{})
+	assert.False(t, ok)
+
+	/// test receiver is rebound to variable of clone
+	var recv *B
+	c := NewContainer()
+	c.ProvideFunc(InjectFunc{Fn: func() *B { return &B{"recv"} }, Receiver: &recv})
+	cc := c.Clone()
+	cc.Populate(nil)
+	assert.Nil(t, recv, "receiver of original container should not be set")
+	obj, ok = cc.CopyOf(&recv)
+	assert.True(t, ok)
+	assert.Equal(t, "recv", (*obj.(**B)).Name)
+	c.Populate(nil)
+	assert.Equal(t, "recv", recv.Name)
+	assert.False(t, recv == *obj.(**B))
+
+	base.Populate(nil)
+	assert.Panics(t, func() {
+		base.Clone()
+	}, "should panic because container is populated")
+}
@@ -0,0 +1,184 @@
+// Command injectgo checks injectgo wiring statically, without running the program.
+//
+// Usage:
+//
+//	injectgo check [-labels a,b] [packages]
+//	injectgo graph [-labels a,b] [-format dot|mermaid|json] [packages]
+//	injectgo gen -func setup [-name setupGenerated] [-labels a,b] [-out injectgo_gen.go] [package]
+//	injectgo fake [-types A,B] [-out injectgo_fake_test.go] [package]
+//
+// check reports unfulfilled names, types and dependency cyclic of every container variable,
+// graph writes the declared graph of every container,
+// gen generates code wiring the container of function setup without reflection, use it with go generate:
+//
+//	//go:generate injectgo gen -func setup -out setup_gen.go
+//
+// fake generates fakes of interfaces required by inject fields of structs A and B, or all structs in package,
+// and registers them to be provided by containers created with injectgotest.WithFakes.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/RivenZoo/injectgo/internal/wiring"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, `usage:
+	injectgo check [-labels a,b] [packages]
+	injectgo graph [-labels a,b] [-format dot|mermaid|json] [packages]
+	injectgo gen -func setup [-name setupGenerated] [-labels a,b] [-out injectgo_gen.go] [package]
+	injectgo fake [-types A,B] [-out injectgo_fake_test.go] [package]`)
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	var err error
+	switch os.Args[1] {
+	case "check":
+		err = check(os.Args[2:], os.Stdout)
+	case "graph":
+		err = graph(os.Args[2:], os.Stdout)
+	case "gen":
+		err = gen(os.Args[2:])
+	case "fake":
+		err = fake(os.Args[2:])
+	default:
+		usage()
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// parseLabels return nil if s is empty so all bindings are selected.
+func parseLabels(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+func patterns(fs *flag.FlagSet) []string {
+	if fs.NArg() == 0 {
+		return []string{"."}
+	}
+	return fs.Args()
+}
+
+func check(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("check", flag.ExitOnError)
+	labels := fs.String("labels", "", "comma separated selected labels, all labels are selected if empty")
+	fs.Parse(args)
+
+	containers, err := wiring.Load("", patterns(fs)...)
+	if err != nil {
+		return err
+	}
+	n := 0
+	for _, c := range containers {
+		for _, p := range c.Check(parseLabels(*labels)) {
+			fmt.Fprintln(w, p)
+			n++
+		}
+	}
+	if n > 0 {
+		return fmt.Errorf("%d problems found", n)
+	}
+	return nil
+}
+
+func graph(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("graph", flag.ExitOnError)
+	labels := fs.String("labels", "", "comma separated selected labels, all labels are selected if empty")
+	format := fs.String("format", "dot", "output format: dot, mermaid or json")
+	fs.Parse(args)
+
+	containers, err := wiring.Load("", patterns(fs)...)
+	if err != nil {
+		return err
+	}
+	for _, c := range containers {
+		g := c.Graph(parseLabels(*labels))
+		switch *format {
+		case "dot":
+			fmt.Fprintf(w, "// %s\n", c.ID)
+			err = g.WriteDOT(w)
+		case "mermaid":
+			fmt.Fprintf(w, "%%%% %s\n", c.ID)
+			err = g.WriteMermaid(w)
+		case "json":
+			err = g.WriteJSON(w)
+		default:
+			return fmt.Errorf("unknown format %s", *format)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func gen(args []string) error {
+	fs := flag.NewFlagSet("gen", flag.ExitOnError)
+	funcName := fs.String("func", "", "function which provides objects and functions to a container")
+	name := fs.String("name", "", "generated function name, default is func name with Generated suffix")
+	labels := fs.String("labels", "", "comma separated selected labels, all labels are selected if empty")
+	out := fs.String("out", "injectgo_gen.go", "output file")
+	fs.Parse(args)
+	if *funcName == "" {
+		usage()
+	}
+	if *name == "" {
+		*name = *funcName + "Generated"
+	}
+
+	containers, err := wiring.Load("", patterns(fs)...)
+	if err != nil {
+		return err
+	}
+	var target *wiring.Container
+	for _, c := range containers {
+		if c.Func.Name.Name != *funcName {
+			continue
+		}
+		if target != nil {
+			return fmt.Errorf("more than one container found in function %s", *funcName)
+		}
+		target = c
+	}
+	if target == nil {
+		return fmt.Errorf("no container found in function %s", *funcName)
+	}
+
+	src, err := wiring.Generate(target, parseLabels(*labels), *name)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(*out, src, 0644)
+}
+
+func fake(args []string) error {
+	fs := flag.NewFlagSet("fake", flag.ExitOnError)
+	typeNames := fs.String("types", "", "comma separated struct names, all structs in package are used if empty")
+	out := fs.String("out", "injectgo_fake_test.go", "output file")
+	fs.Parse(args)
+	if fs.NArg() > 1 {
+		usage()
+	}
+
+	src, err := wiring.GenerateFakes("", patterns(fs)[0], parseLabels(*typeNames))
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(*out, src, 0644)
+}
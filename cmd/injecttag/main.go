@@ -0,0 +1,14 @@
+// Command injecttag checks inject tags and InjectFunc values, run it by go vet:
+//
+//	go install github.com/RivenZoo/injectgo/cmd/injecttag
+//	go vet -vettool=$(which injecttag) ./...
+package main
+
+import (
+	"github.com/RivenZoo/injectgo/analysis/injecttag"
+	"golang.org/x/tools/go/analysis/unitchecker"
+)
+
+func main() {
+	unitchecker.Main(injecttag.Analyzer)
+}
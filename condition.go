@@ -0,0 +1,142 @@
+package injectgo
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Bindings is the view of objects and functions a Condition is evaluated against.
+// It contains selected objects, selected functions without condition
+// and conditional functions matched before.
+type Bindings interface {
+	// HasName return true if an object or function is provided by name.
+	HasName(name string) bool
+	// HasType return true if an unnamed object or function is assignable to tp.
+	HasType(tp reflect.Type) bool
+}
+
+// Condition decides whether an InjectFunc is called or not.
+type Condition interface {
+	Match(b Bindings) bool
+	String() string
+}
+
+// ConditionResult records whether a condition of a function matched in Populate.
+type ConditionResult struct {
+	Name      string       // function name, empty if function is unnamed
+	Type      reflect.Type // function return type
+	Condition string
+	Matched   bool
+}
+
+func (r ConditionResult) String() string {
+	name := r.Name
+	if name == "" {
+		name = "<unnamed>"
+	}
+	return fmt.Sprintf("%s(%v) %s matched: %t", name, r.Type, r.Condition, r.Matched)
+}
+
+type typeCondition struct {
+	tp      reflect.Type
+	present bool
+}
+
+func (c typeCondition) Match(b Bindings) bool {
+	return b.HasType(c.tp) == c.present
+}
+
+func (c typeCondition) String() string {
+	if c.present {
+		return fmt.Sprintf("OnPresentType(%v)", c.tp)
+	}
+	return fmt.Sprintf("OnMissingType(%v)", c.tp)
+}
+
+type nameCondition struct {
+	name    string
+	present bool
+}
+
+func (c nameCondition) Match(b Bindings) bool {
+	return b.HasName(c.name) == c.present
+}
+
+func (c nameCondition) String() string {
+	if c.present {
+		return fmt.Sprintf("OnPresentName(%s)", c.name)
+	}
+	return fmt.Sprintf("OnMissingName(%s)", c.name)
+}
+
+type predicateCondition struct {
+	desc string
+	fn   func(Bindings) bool
+}
+
+func (c predicateCondition) Match(b Bindings) bool {
+	return c.fn(b)
+}
+
+func (c predicateCondition) String() string {
+	return fmt.Sprintf("OnCondition(%s)", c.desc)
+}
+
+// OnMissingType matches if no unnamed object or function is assignable to typ.
+// Use (*Iface)(nil) for interface type, (*T)(nil) for pointer type *T.
+func OnMissingType(typ interface{}) Condition {
+	return typeCondition{tp: bindingType(typ), present: false}
+}
+
+// OnPresentType matches if any unnamed object or function is assignable to typ.
+func OnPresentType(typ interface{}) Condition {
+	return typeCondition{tp: bindingType(typ), present: true}
+}
+
+// OnMissingName matches if no object or function is provided by name.
+func OnMissingName(name string) Condition {
+	return nameCondition{name: name, present: false}
+}
+
+// OnPresentName matches if an object or function is provided by name.
+func OnPresentName(name string) Condition {
+	return nameCondition{name: name, present: true}
+}
+
+// OnCondition matches if fn return true. Param desc is used in ConditionResult.
+func OnCondition(desc string, fn func(Bindings) bool) Condition {
+	return predicateCondition{desc: desc, fn: fn}
+}
+
+type bindingSet struct {
+	names map[string]bool
+	types []reflect.Type
+}
+
+func newBindingSet() *bindingSet {
+	return &bindingSet{
+		names: make(map[string]bool),
+		types: make([]reflect.Type, 0),
+	}
+}
+
+func (s *bindingSet) HasName(name string) bool {
+	return s.names[name]
+}
+
+func (s *bindingSet) HasType(tp reflect.Type) bool {
+	for i := range s.types {
+		if s.types[i].AssignableTo(tp) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *bindingSet) addName(name string) {
+	s.names[name] = true
+}
+
+func (s *bindingSet) addType(tp reflect.Type) {
+	s.types = append(s.types, tp)
+}
@@ -0,0 +1,198 @@
+package injectgo
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type conditionCache interface {
+	Get(key string) string
+}
+
+type memoryCache struct{}
+
+func (c *memoryCache) Get(key string) string {
+	return "memory"
+}
+
+type redisCache struct{}
+
+func (c *redisCache) Get(key string) string {
+	return "redis"
+}
+
+type metricsConfig struct{}
+
+type metricsExporter struct {
+	Config *metricsConfig `inject:""`
+}
+
+type cacheUser struct {
+	Cache conditionCache `inject:""`
+}
+
+func TestBindingType(t *testing.T) {
+	assert.Equal(t, reflect.TypeOf((*conditionCache)(nil)).Elem(), bindingType((*conditionCache)(nil)))
+	assert.Equal(t, reflect.TypeOf(&metricsConfig{}), bindingType((*metricsConfig)(nil)))
+	assert.Equal(t, reflect.TypeOf(1), bindingType(reflect.TypeOf(1)))
+}
+
+func TestCondition_OnMissingType(t *testing.T) {
+	defaultCache := InjectFunc{
+		Fn:         func() conditionCache { return &memoryCache{} },
+		Conditions: []Condition{OnMissingType((*conditionCache)(nil))},
+	}
+
+	u := &cacheUser{}
+	c := NewContainer()
+	c.Provide(u)
+	c.ProvideFunc(defaultCache)
+	c.Populate(nil)
+	assert.Equal(t, "memory", u.Cache.Get(""))
+	assert.Len(t, c.ConditionReport(), 1)
+	assert.True(t, c.ConditionReport()[0].Matched)
+	t.Log(c.ConditionReport())
+
+	u = &cacheUser{}
+	c = NewContainer()
+	c.Provide(u)
+	c.ProvideFunc(defaultCache)
+	c.Provide(&redisCache{})
+	c.Populate(nil)
+	assert.Equal(t, "redis", u.Cache.Get(""))
+	assert.False(t, c.ConditionReport()[0].Matched)
+
+	/// test function provided cache
+	u = &cacheUser{}
+	c = NewContainer()
+	c.Provide(u)
+	c.ProvideFunc(defaultCache, InjectFunc{
+		Fn: func() *redisCache { return &redisCache{} },
+	})
+	c.Populate(nil)
+	assert.Equal(t, "redis", u.Cache.Get(""))
+
+	/// test unselected function is not considered
+	u = &cacheUser{}
+	c = NewContainer()
+	c.Provide(u)
+	c.ProvideFunc(defaultCache, InjectFunc{
+		Fn:    func() *redisCache { return &redisCache{} },
+		Label: "prod",
+	})
+	c.Populate(labelSelector{})
+	assert.Equal(t, "memory", u.Cache.Get(""))
+}
+
+func TestCondition_OnPresentType(t *testing.T) {
+	var exporter *metricsExporter
+	ifn := InjectFunc{
+		Fn:         func() *metricsExporter { return &metricsExporter{} },
+		Receiver:   &exporter,
+		Conditions: []Condition{OnPresentType((*metricsConfig)(nil))},
+	}
+
+	c := NewContainer()
+	c.ProvideFunc(ifn)
+	c.Populate(nil)
+	assert.Nil(t, exporter)
+
+	c = NewContainer()
+	c.ProvideFunc(ifn)
+	c.Provide(&metricsConfig{})
+	c.Populate(nil)
+	assert.NotNil(t, exporter)
+	assert.NotNil(t, exporter.Config)
+}
+
+func TestCondition_Names(t *testing.T) {
+	type NamedCacheUser struct {
+		Cache conditionCache `inject:"cache"`
+	}
+
+	var calls []string
+	u := &NamedCacheUser{}
+	c := NewContainer()
+	c.Provide(u)
+	c.ProvideFuncByName("cache", InjectFunc{
+		Fn: func() conditionCache {
+			calls = append(calls, "memory")
+			return &memoryCache{}
+		},
+		Conditions: []Condition{OnMissingName("redis")},
+	})
+	c.ProvideFuncByName("redis", InjectFunc{
+		Fn: func() *redisCache {
+			calls = append(calls, "redis")
+			return &redisCache{}
+		},
+		Conditions: []Condition{
+			OnPresentName("cache"),
+			OnCondition("always", func(b Bindings) bool { return true }),
+		},
+	})
+	c.Populate(nil)
+
+	// "cache" is evaluated first and matched, so "redis" sees it
+	assert.Equal(t, []string{"memory", "redis"}, calls)
+	assert.Equal(t, "memory", u.Cache.Get(""))
+
+	report := c.ConditionReport()
+	assert.Len(t, report, 3)
+	assert.Equal(t, "cache", report[0].Name)
+	assert.Equal(t, "OnMissingName(redis)", report[0].Condition)
+	assert.Equal(t, "redis", report[1].Name)
+	assert.Equal(t, "OnCondition(always)", report[2].Condition)
+	for i := range report {
+		assert.True(t, report[i].Matched)
+	}
+}
+
+func TestCondition_ProvidedOrder(t *testing.T) {
+	u := &cacheUser{}
+	c := NewContainer()
+	c.Provide(u)
+	// named function is provided first, so unnamed default cache sees it
+	c.ProvideFuncByName("redis", InjectFunc{
+		Fn:         func() *redisCache { return &redisCache{} },
+		Conditions: []Condition{OnMissingType((*metricsConfig)(nil))},
+	})
+	c.ProvideFunc(InjectFunc{
+		Fn:         func() conditionCache { return &memoryCache{} },
+		Conditions: []Condition{OnMissingName("redis")},
+	})
+	assert.Panics(t, func() {
+		c.Populate(nil)
+	}, "should panic because default cache is not created")
+
+	report := c.ConditionReport()
+	assert.Len(t, report, 2)
+	assert.Equal(t, "redis", report[0].Name)
+	assert.True(t, report[0].Matched)
+	assert.Equal(t, "", report[1].Name)
+	assert.False(t, report[1].Matched)
+}
+
+func TestFunc_InterfaceResult(t *testing.T) {
+	type concreteUser struct {
+		Cache *memoryCache `inject:""`
+	}
+
+	u := &cacheUser{}
+	c := NewContainer()
+	c.Provide(u)
+	c.ProvideFunc(InjectFunc{Fn: func() conditionCache { return &memoryCache{} }})
+	c.Populate(nil)
+	assert.Equal(t, "memory", u.Cache.Get(""))
+	_, ok := c.LookupType((*conditionCache)(nil))
+	assert.True(t, ok, "object should be bound to declared return type")
+
+	c = NewContainer()
+	c.Provide(&concreteUser{})
+	c.ProvideFunc(InjectFunc{Fn: func() conditionCache { return &memoryCache{} }})
+	assert.Panics(t, func() {
+		c.Populate(nil)
+	}, "should panic because object returned as interface does not fulfill field of its concrete type")
+}
@@ -35,6 +35,7 @@ func (d *cyclicDetector) AddDetectObject(v reflect.Value) {
 		return
 	}
 	ts := typeSet{}
+	d.typeDeps[t] = ts
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
 		if inj, ok := field.Tag.Lookup(injectTag); ok {
@@ -43,12 +44,15 @@ func (d *cyclicDetector) AddDetectObject(v reflect.Value) {
 				// interface no need to detect cyclic
 			case reflect.Ptr:
 				ts[field.Type.Elem()] = true
+				if inj == privateTag {
+					// object created for private field is not provided, detect its fields
+					d.AddDetectObject(reflect.New(field.Type.Elem()))
+				}
 			default:
 				panic(fmt.Errorf("field %v inject tag %s of object %v wrong type", field, inj, v))
 			}
 		}
 	}
-	d.typeDeps[t] = ts
 }
 
 func (d *cyclicDetector) DetectCyclic() (cyclicExists bool, cyclic depPath) {
@@ -0,0 +1,135 @@
+package injectgo
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// decorator wraps objects of type tp, or object provided by name if name is not empty.
+type decorator struct {
+	tp   reflect.Type
+	name string
+	fn   reflect.Value
+}
+
+func (d decorator) isMatch(name string, tp reflect.Type) bool {
+	if d.name != "" {
+		return d.name == name
+	}
+	return tp.AssignableTo(d.tp)
+}
+
+func (d decorator) call(v reflect.Value) reflect.Value {
+	ret := d.fn.Call([]reflect.Value{v})
+	if len(ret) == 2 && !ret[1].IsNil() {
+		panic(fmt.Errorf("decorate %v error: %v", v.Type(), ret[1].Interface()))
+	}
+	return concreteValue(ret[0])
+}
+
+// validateDecorator panics if fn is not func(T) T / func(T) (T, error).
+func validateDecorator(tp reflect.Type, fn interface{}) reflect.Value {
+	fv := reflect.ValueOf(fn)
+	if fv.Kind() != reflect.Func {
+		panic(errValueNotFunction)
+	}
+	t := fv.Type()
+	if t.NumIn() != 1 || (tp != nil && t.In(0) != tp) {
+		panic(fmt.Errorf("decorator %v should accept one %v argument", t, tp))
+	}
+	if t.NumOut() <= 0 || t.NumOut() > 2 || t.Out(0) != t.In(0) {
+		panic(fmt.Errorf("decorator %v should return %v", t, t.In(0)))
+	}
+	if t.NumOut() == 2 && t.Out(1) != reflect.TypeOf((*error)(nil)).Elem() {
+		panic(fmt.Errorf("decorator %v second return value should be error", t))
+	}
+	return fv
+}
+
+// Decorate wraps every object assignable to ifaceOrType with fn before injected, named objects included.
+// Param ifaceOrType likes (*Iface)(nil) for interface type or (*T)(nil) for *T.
+// Param fn should be func(T) T / func(T) (T, error), otherwise it panics.
+// Decorators are applied in added order, the first added wraps the original object.
+// Object returned by fn is populated like provided objects, so it can declare inject fields
+// for its own dependencies. Wrapped object is still populated, initialized and closed.
+// Objects of ScopeTransient functions are not decorated, it panics if fn matches one of them.
+func (c *Container) Decorate(ifaceOrType interface{}, fn interface{}) {
+	tp := bindingType(ifaceOrType)
+	d := decorator{tp: tp, fn: validateDecorator(tp, fn)}
+
+	c.lockRegistering()
+	defer c.mu.Unlock()
+	c.addDecorator(d)
+}
+
+// DecorateByName works like Decorate but only wraps object provided by name.
+func (c *Container) DecorateByName(name string, fn interface{}) {
+	d := decorator{name: name, fn: validateDecorator(nil, fn)}
+
+	c.lockRegistering()
+	defer c.mu.Unlock()
+	c.addDecorator(d)
+}
+
+func (c *Container) addDecorator(d decorator) {
+	for _, ifn := range c.unnamedFunctions {
+		if err := d.checkTransient("", ifn); err != nil {
+			panic(err)
+		}
+	}
+	for name, ifn := range c.namedFunctions {
+		if err := d.checkTransient(name, ifn); err != nil {
+			panic(err)
+		}
+	}
+	c.decorators = append(c.decorators, d)
+}
+
+// checkTransient return error if d matches transient function, its objects are created while injecting
+// and never decorated.
+func (d decorator) checkTransient(name string, ifn InjectFunc) error {
+	if ifn.Scope != ScopeTransient || !d.isMatch(name, ifn.returnType()) {
+		return nil
+	}
+	return fmt.Errorf("decorator %v can not decorate transient function %s(%v)", d.fn.Type(), name, ifn.returnType())
+}
+
+// checkDecorated return error if any decorator matches transient function ifn.
+func (c *Container) checkDecorated(name string, ifn InjectFunc) error {
+	for _, d := range c.decorators {
+		if err := d.checkTransient(name, ifn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decorate apply matching decorators to v in added order and record wrapped objects.
+// It return decorated object and index of object it wraps, -1 if not decorated.
+func (c *Container) decorate(v reflect.Value, b binding) (reflect.Value, int) {
+	wraps := -1
+	for i := range c.decorators {
+		d := c.decorators[i]
+		if !d.isMatch(b.name, v.Type()) {
+			continue
+		}
+		if !v.Type().AssignableTo(d.fn.Type().In(0)) {
+			panic(fmt.Errorf("decorator %v can not decorate object %s(%v)", d.fn.Type(), b.name, v.Type()))
+		}
+		wrapped := d.call(v)
+		wraps = c.selectInnerValue(v, b, wraps)
+		v = wrapped
+	}
+	return v, wraps
+}
+
+func (c *Container) selectInnerValue(v reflect.Value, b binding, wraps int) int {
+	// extract injected struct fields, inner object fulfill nothing
+	c.checker.pushInjectedFields(v)
+
+	// add cyclic detector
+	c.detector.AddDetectObject(v)
+
+	c.selectedInnerValues = append(c.selectedInnerValues, v)
+	return c.recordSelected(v, b, true, wraps)
+}
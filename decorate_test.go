@@ -0,0 +1,119 @@
+package injectgo
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type decorateLogger interface {
+	Log(msg string) string
+}
+
+type baseLogger struct {
+	Prefix *decoratePrefix `inject:""`
+	inited bool
+}
+
+func (l *baseLogger) Init() error {
+	l.inited = true
+	return nil
+}
+
+func (l *baseLogger) Log(msg string) string {
+	return l.Prefix.Value + msg
+}
+
+type decoratePrefix struct {
+	Value string
+}
+
+type decorateMetrics struct {
+	count int
+}
+
+type metricsLogger struct {
+	inner   decorateLogger
+	Metrics *decorateMetrics `inject:""`
+}
+
+func (l *metricsLogger) Log(msg string) string {
+	l.Metrics.count++
+	return l.inner.Log(msg)
+}
+
+type tracingLogger struct {
+	inner decorateLogger
+}
+
+func (l *tracingLogger) Log(msg string) string {
+	return fmt.Sprintf("trace(%s)", l.inner.Log(msg))
+}
+
+type loggerUser struct {
+	Logger decorateLogger `inject:""`
+	Named  decorateLogger `inject:"named"`
+}
+
+func TestContainer_Decorate(t *testing.T) {
+	base := &baseLogger{}
+	named := &baseLogger{}
+	metrics := &decorateMetrics{}
+	u := &loggerUser{}
+
+	c := NewContainer()
+	c.Provide(u, base, metrics, &decoratePrefix{"> "})
+	c.ProvideByName("named", named)
+	c.Decorate((*decorateLogger)(nil), func(l decorateLogger) decorateLogger {
+		return &metricsLogger{inner: l}
+	})
+	c.Decorate((*decorateLogger)(nil), func(l decorateLogger) (decorateLogger, error) {
+		return &tracingLogger{inner: l}, nil
+	})
+	c.DecorateByName("named", func(l decorateLogger) decorateLogger {
+		return &tracingLogger{inner: l}
+	})
+	c.Populate(nil)
+
+	assert.Equal(t, "trace(> a)", u.Logger.Log("a"))
+	assert.Equal(t, 1, metrics.count)
+	assert.Equal(t, "trace(trace(> b))", u.Named.Log("b"))
+	assert.Equal(t, 2, metrics.count)
+
+	// wrapped objects are populated and initialized
+	assert.NotNil(t, base.Prefix)
+	assert.True(t, base.inited)
+	assert.True(t, named.inited)
+}
+
+func TestContainer_DecorateUnfulfilled(t *testing.T) {
+	c := NewContainer()
+	c.Provide(&loggerUser{}, &baseLogger{}, &decoratePrefix{})
+	c.ProvideByName("named", &baseLogger{})
+	c.Decorate((*decorateLogger)(nil), func(l decorateLogger) decorateLogger {
+		return &metricsLogger{inner: l}
+	})
+	assert.Panics(t, func() {
+		defer func() {
+			if e := recover(); e != nil {
+				t.Log(e)
+				panic(e)
+			}
+		}()
+		c.Populate(nil)
+	}, "should panic because decorator dependency not provided")
+}
+
+func TestContainer_DecorateWrongFunc(t *testing.T) {
+	c := NewContainer()
+	assert.Panics(t, func() {
+		c.Decorate((*decorateLogger)(nil), func(l *baseLogger) decorateLogger { return l })
+	})
+	assert.Panics(t, func() {
+		c.Decorate((*decorateLogger)(nil), func(l decorateLogger) (decorateLogger, string) { return l, "" })
+	})
+	assert.Panics(t, func() {
+		c.DecorateByName("named", "not function")
+	})
+}
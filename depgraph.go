@@ -0,0 +1,243 @@
+package injectgo
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// NodeKind is the kind of a graph node.
+type NodeKind string
+
+// Graph node kinds.
+const (
+	NodeObject    NodeKind = "object"    // provided object
+	NodeFunction  NodeKind = "function"  // provided function, or object returned by it
+	NodeDecorator NodeKind = "decorator" // object returned by decorator
+)
+
+// Node is a provided object or function.
+type Node struct {
+	ID       string   `json:"id"`
+	Kind     NodeKind `json:"kind"`
+	Type     string   `json:"type"`
+	Declared string   `json:"declared,omitempty"` // declared type if it differs from Type, eg. interface returned by function
+	Name     string   `json:"name,omitempty"`
+	Label    string   `json:"label,omitempty"`
+	Site     string   `json:"site,omitempty"`    // file:line where object or function is provided
+	Module   string   `json:"module,omitempty"`  // name of module installs object or function
+	Private  bool     `json:"private,omitempty"` // only injected into objects of the same module
+	Inner    bool     `json:"inner,omitempty"`   // wrapped by decorator, never injected
+	Wraps    string   `json:"wraps,omitempty"`   // id of node wrapped by decorator
+}
+
+// Edge is an inject field of From node which is satisfied by To node.
+type Edge struct {
+	From  string `json:"from"`
+	To    string `json:"to,omitempty"` // empty if no node satisfies the field
+	Field string `json:"field"`
+	Tag   string `json:"tag"`
+}
+
+// Graph is the dependency graph of a container.
+// Before Populate it is the declared graph of all provided objects and functions,
+// edges are resolved by names and types like Populate does.
+// After Populate it is the resolved graph of selected objects, edges point to the injected objects.
+type Graph struct {
+	Resolved bool   `json:"resolved"`
+	Nodes    []Node `json:"nodes"`
+	Edges    []Edge `json:"edges"`
+}
+
+// Node return node by id.
+func (g *Graph) Node(id string) (Node, bool) {
+	for i := range g.Nodes {
+		if g.Nodes[i].ID == id {
+			return g.Nodes[i], true
+		}
+	}
+	return Node{}, false
+}
+
+// Graph return the declared graph before Populate, resolved graph after Populate.
+func (c *Container) Graph() *Graph {
+	c.rlockPopulated()
+	defer c.mu.RUnlock()
+	return c.currentGraph()
+}
+
+func (c *Container) currentGraph() *Graph {
+	if c.populated {
+		return c.resolvedGraph()
+	}
+	return c.declaredGraph()
+}
+
+func nodeID(i int) string {
+	return fmt.Sprintf("n%d", i)
+}
+
+func (c *Container) sortedValueNames() []string {
+	names := make([]string, 0, len(c.namedValues))
+	for name := range c.namedValues {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// graphNode is a node and the type used to resolve edges.
+type graphNode struct {
+	Node
+	tp    reflect.Type
+	value reflect.Value // invalid in declared graph
+}
+
+func (c *Container) declaredGraph() *Graph {
+	nodes := make([]graphNode, 0)
+	add := func(tp reflect.Type, b binding) {
+		nodes = append(nodes, graphNode{
+			Node: Node{ID: nodeID(len(nodes)), Kind: b.kind, Type: tp.String(), Name: b.name, Label: b.label, Site: b.site, Module: b.module, Private: b.private},
+			tp:   tp,
+		})
+	}
+	for i := range c.unnamedValues {
+		pv := c.unnamedValues[i]
+		add(pv.value.Type(), binding{label: pv.label, site: pv.site, module: pv.module, private: pv.private, kind: NodeObject})
+	}
+	for _, name := range c.sortedValueNames() {
+		pv := c.namedValues[name]
+		add(pv.value.Type(), binding{name: name, label: pv.label, site: pv.site, module: pv.module, private: pv.private, kind: NodeObject})
+	}
+	for i := range c.unnamedFunctions {
+		ifn := c.unnamedFunctions[i]
+		add(ifn.returnType(), binding{label: ifn.Label, site: ifn.site, module: ifn.module, private: ifn.private, kind: NodeFunction})
+	}
+	for _, name := range c.namedFuncOrder {
+		ifn := c.namedFunctions[name]
+		add(ifn.returnType(), binding{name: name, label: ifn.Label, site: ifn.site, module: ifn.module, private: ifn.private, kind: NodeFunction})
+	}
+
+	g := &Graph{Nodes: make([]Node, 0, len(nodes)), Edges: make([]Edge, 0)}
+	for i := range nodes {
+		g.Nodes = append(g.Nodes, nodes[i].Node)
+		for _, f := range graphFields(nodes[i].tp) {
+			e := Edge{From: nodes[i].ID, Field: f.Name, Tag: f.Tag.Get(injectTag)}
+			if to := declaredTarget(nodes, e.Tag, f.Type); to != nil {
+				e.To = to.ID
+			}
+			g.Edges = append(g.Edges, e)
+		}
+	}
+	return g
+}
+
+// declaredTarget find node by name if tag is not empty, otherwise unnamed node
+// whose type is the same as or assignable to field type.
+func declaredTarget(nodes []graphNode, tag string, fieldType reflect.Type) *graphNode {
+	if tag != "" {
+		for i := range nodes {
+			if nodes[i].Name == tag {
+				return &nodes[i]
+			}
+		}
+		return nil
+	}
+	for i := range nodes {
+		if nodes[i].Name == "" && nodes[i].tp == fieldType {
+			return &nodes[i]
+		}
+	}
+	for i := range nodes {
+		if nodes[i].Name == "" && nodes[i].tp.AssignableTo(fieldType) {
+			return &nodes[i]
+		}
+	}
+	return nil
+}
+
+func (c *Container) resolvedGraph() *Graph {
+	nodes := make([]graphNode, 0, len(c.selectedObjects))
+	for i := range c.selectedObjects {
+		o := c.selectedObjects[i]
+		// object returned as interface is shown and matched by its implementation
+		v := concreteValue(o.value)
+		n := graphNode{
+			Node: Node{ID: nodeID(i), Kind: o.kind, Type: v.Type().String(),
+				Name: o.name, Label: o.label, Site: o.site, Module: o.module, Private: o.private, Inner: o.inner},
+			tp:    v.Type(),
+			value: v,
+		}
+		if v.Type() != o.value.Type() {
+			n.Declared = o.value.Type().String()
+		}
+		if o.wraps >= 0 {
+			n.Wraps = nodeID(o.wraps)
+		}
+		nodes = append(nodes, n)
+	}
+
+	g := &Graph{Resolved: true, Nodes: make([]Node, 0, len(nodes)), Edges: make([]Edge, 0)}
+	for i := range nodes {
+		g.Nodes = append(g.Nodes, nodes[i].Node)
+		rawV := reflect.Indirect(nodes[i].value)
+		for _, f := range graphFields(nodes[i].tp) {
+			e := Edge{From: nodes[i].ID, Field: f.Name, Tag: f.Tag.Get(injectTag)}
+			if to := resolvedTarget(nodes, e.Tag, rawV.FieldByIndex(f.Index)); to != nil {
+				e.To = to.ID
+			}
+			g.Edges = append(g.Edges, e)
+		}
+	}
+	return g
+}
+
+// resolvedTarget find the node whose object is the field value,
+// prefer node named by tag or unnamed node if tag is empty.
+func resolvedTarget(nodes []graphNode, tag string, field reflect.Value) *graphNode {
+	field = concreteValue(field)
+	if !field.IsValid() || (field.Kind() == reflect.Ptr && field.IsNil()) || field.Kind() == reflect.Interface {
+		return nil
+	}
+	var found *graphNode
+	for i := range nodes {
+		n := &nodes[i]
+		if n.Inner || !isSameObject(n.value, field) {
+			continue
+		}
+		if n.Name == tag {
+			return n
+		}
+		if found == nil {
+			found = n
+		}
+	}
+	return found
+}
+
+func isSameObject(a, b reflect.Value) bool {
+	if a.Type() != b.Type() {
+		return false
+	}
+	if a.Kind() == reflect.Ptr {
+		return a.Pointer() == b.Pointer()
+	}
+	return a.Type().Comparable() && a.Interface() == b.Interface()
+}
+
+// graphFields return inject fields of struct tp or tp points to.
+func graphFields(tp reflect.Type) []reflect.StructField {
+	if tp.Kind() == reflect.Ptr {
+		tp = tp.Elem()
+	}
+	if tp.Kind() != reflect.Struct {
+		return nil
+	}
+	fields := make([]reflect.StructField, 0)
+	for i := 0; i < tp.NumField(); i++ {
+		if _, ok := tp.Field(i).Tag.Lookup(injectTag); ok {
+			fields = append(fields, tp.Field(i))
+		}
+	}
+	return fields
+}
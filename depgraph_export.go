@@ -0,0 +1,120 @@
+package injectgo
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// nodeText return lines describing node.
+func nodeText(n Node) []string {
+	lines := []string{n.Type}
+	if n.Name != "" {
+		lines = append(lines, fmt.Sprintf("name: %s", n.Name))
+	}
+	if n.Label != "" {
+		lines = append(lines, fmt.Sprintf("label: %s", n.Label))
+	}
+	if n.Module != "" {
+		lines = append(lines, fmt.Sprintf("module: %s", n.Module))
+	}
+	if n.Private {
+		lines = append(lines, "private")
+	}
+	return lines
+}
+
+// missingID return id of the placeholder node of unresolved edge.
+func missingID(i int) string {
+	return fmt.Sprintf("missing%d", i)
+}
+
+// edgeText return field name and tag name if field is injected by name.
+func edgeText(e Edge) string {
+	if e.Tag != "" {
+		return fmt.Sprintf("%s (%s)", e.Field, e.Tag)
+	}
+	return e.Field
+}
+
+// WriteDOT write graph in Graphviz DOT format.
+func (g *Graph) WriteDOT(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph injectgo {")
+	fmt.Fprintln(bw, "\trankdir=LR;")
+	for _, n := range g.Nodes {
+		shape := "box"
+		switch n.Kind {
+		case NodeFunction:
+			shape = "ellipse"
+		case NodeDecorator:
+			shape = "hexagon"
+		}
+		label := strings.Join(nodeText(n), "\n")
+		fmt.Fprintf(bw, "\t%s [label=%q shape=%s];\n", n.ID, label, shape)
+	}
+	for _, n := range g.Nodes {
+		if n.Wraps != "" {
+			fmt.Fprintf(bw, "\t%s -> %s [label=\"wraps\" style=dashed];\n", n.ID, n.Wraps)
+		}
+	}
+	for i, e := range g.Edges {
+		to := e.To
+		if to == "" {
+			to = missingID(i)
+			fmt.Fprintf(bw, "\t%s [label=\"unresolved\" color=red];\n", to)
+		}
+		fmt.Fprintf(bw, "\t%s -> %s [label=%q];\n", e.From, to, edgeText(e))
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// mermaidText escape s to be used in mermaid quoted text.
+func mermaidText(s string) string {
+	return strings.NewReplacer(`"`, "#quot;", "<", "#lt;", ">", "#gt;").Replace(s)
+}
+
+// WriteMermaid write graph in Mermaid flowchart format.
+func (g *Graph) WriteMermaid(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "graph LR")
+	for _, n := range g.Nodes {
+		lines := nodeText(n)
+		for i := range lines {
+			lines[i] = mermaidText(lines[i])
+		}
+		label := strings.Join(lines, "<br/>")
+		switch n.Kind {
+		case NodeFunction:
+			fmt.Fprintf(bw, "\t%s([\"%s\"])\n", n.ID, label)
+		case NodeDecorator:
+			fmt.Fprintf(bw, "\t%s{{\"%s\"}}\n", n.ID, label)
+		default:
+			fmt.Fprintf(bw, "\t%s[\"%s\"]\n", n.ID, label)
+		}
+	}
+	for _, n := range g.Nodes {
+		if n.Wraps != "" {
+			fmt.Fprintf(bw, "\t%s -.->|wraps| %s\n", n.ID, n.Wraps)
+		}
+	}
+	for i, e := range g.Edges {
+		to := e.To
+		if to == "" {
+			to = missingID(i)
+			fmt.Fprintf(bw, "\t%s[\"unresolved\"]\n", to)
+		}
+		fmt.Fprintf(bw, "\t%s -->|\"%s\"| %s\n", e.From, mermaidText(edgeText(e)), to)
+	}
+	return bw.Flush()
+}
+
+// WriteJSON write graph in indented JSON format.
+func (g *Graph) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(g)
+}
@@ -0,0 +1,153 @@
+package injectgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type graphClient struct {
+	Addr string
+}
+
+type graphService struct {
+	Client   *graphClient `inject:""`
+	Named    *graphClient `inject:"named"`
+	Stringer fmt.Stringer `inject:""`
+}
+
+func provideGraphWiring(c *Container) (*graphService, *graphClient) {
+	s := &graphService{}
+	cli := &graphClient{}
+	c.Provide(s, cli)
+	c.ProvideWithLabel("prod", &Person{})
+	c.ProvideFuncByName("named", InjectFunc{
+		Fn: func() *graphClient { return &graphClient{Addr: "named"} },
+	})
+	return s, cli
+}
+
+func findEdge(g *Graph, from, field string) Edge {
+	for _, e := range g.Edges {
+		if e.From == from && e.Field == field {
+			return e
+		}
+	}
+	return Edge{}
+}
+
+func TestContainer_DeclaredGraph(t *testing.T) {
+	c := NewContainer()
+	provideGraphWiring(c)
+	c.ProvideFunc(InjectFunc{
+		Fn: func() fmt.Stringer { return &Person{} },
+	})
+
+	g := c.Graph()
+	assert.False(t, g.Resolved)
+	assert.Len(t, g.Nodes, 5)
+	assert.Len(t, g.Edges, 3)
+
+	assert.Equal(t, "*injectgo.graphService", g.Nodes[0].Type)
+	assert.Equal(t, NodeObject, g.Nodes[0].Kind)
+	assert.Contains(t, g.Nodes[0].Site, "depgraph_test.go")
+	assert.Equal(t, "prod", g.Nodes[2].Label)
+	assert.Equal(t, "fmt.Stringer", g.Nodes[3].Type)
+	assert.Equal(t, "named", g.Nodes[4].Name)
+	assert.Equal(t, NodeFunction, g.Nodes[4].Kind)
+
+	assert.Equal(t, "n1", findEdge(g, "n0", "Client").To)
+	assert.Equal(t, "n4", findEdge(g, "n0", "Named").To)
+	assert.Equal(t, "named", findEdge(g, "n0", "Named").Tag)
+	// function returns fmt.Stringer is preferred to Person assignable to fmt.Stringer
+	assert.Equal(t, "n3", findEdge(g, "n0", "Stringer").To)
+
+	c = NewContainer()
+	c.Provide(&graphService{})
+	g = c.Graph()
+	assert.Equal(t, "", findEdge(g, "n0", "Client").To)
+}
+
+func TestContainer_ResolvedGraph(t *testing.T) {
+	c := NewContainer()
+	_, cli := provideGraphWiring(c)
+	c.Decorate((*fmt.Stringer)(nil), func(s fmt.Stringer) fmt.Stringer { return &Person{Name: "decorated"} })
+	c.Populate(nil)
+
+	g := c.Graph()
+	assert.True(t, g.Resolved)
+	// service, client, inner person, decorated person, named client
+	assert.Len(t, g.Nodes, 5)
+	assert.True(t, g.Nodes[2].Inner)
+	assert.Equal(t, NodeDecorator, g.Nodes[3].Kind)
+	assert.Equal(t, "n2", g.Nodes[3].Wraps)
+
+	client, ok := g.Node(findEdge(g, "n0", "Client").To)
+	assert.True(t, ok)
+	assert.Equal(t, "*injectgo.graphClient", client.Type)
+	assert.Equal(t, "n1", client.ID)
+	assert.Equal(t, "named", findEdge(g, "n0", "Named").Tag)
+	named, _ := g.Node(findEdge(g, "n0", "Named").To)
+	assert.Equal(t, "named", named.Name)
+	assert.Equal(t, NodeFunction, named.Kind)
+	assert.Equal(t, "n3", findEdge(g, "n0", "Stringer").To)
+	assert.NotNil(t, cli)
+}
+
+func TestContainer_ResolvedGraphInterfaceResult(t *testing.T) {
+	c := NewContainer()
+	provideGraphWiring(c)
+	c.ProvideFunc(InjectFunc{
+		Fn: func() fmt.Stringer { return &Person{Name: "func"} },
+	})
+	c.Populate(labelSelector{labels: []string{"dev"}})
+
+	g := c.Graph()
+	stringer, ok := g.Node(findEdge(g, "n0", "Stringer").To)
+	assert.True(t, ok, "field injected with interface result should be resolved")
+	assert.Equal(t, "*injectgo.Person", stringer.Type)
+	assert.Equal(t, NodeFunction, stringer.Kind)
+	assert.Contains(t, c.Manifest(), "*injectgo.graphService.Stringer `inject:\"\"` <- *injectgo.Person [function]")
+}
+
+type graphMissing struct {
+	Missing *B `inject:"missing"`
+}
+
+func TestGraph_Export(t *testing.T) {
+	c := NewContainer()
+	provideGraphWiring(c)
+	g := c.Graph()
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, g.WriteDOT(buf))
+	dot := buf.String()
+	assert.Contains(t, dot, "digraph injectgo {")
+	assert.Contains(t, dot, `n0 -> n1 [label="Client"];`)
+	assert.Contains(t, dot, `n0 -> n3 [label="Named (named)"];`)
+	t.Log(dot)
+
+	buf.Reset()
+	assert.NoError(t, g.WriteMermaid(buf))
+	mermaid := buf.String()
+	assert.Contains(t, mermaid, "graph LR")
+	assert.Contains(t, mermaid, `n0 -->|"Client"| n1`)
+	assert.Contains(t, mermaid, `n3(["*injectgo.graphClient<br/>name: named"])`)
+	t.Log(mermaid)
+
+	buf.Reset()
+	assert.NoError(t, g.WriteJSON(buf))
+	decoded := &Graph{}
+	assert.NoError(t, json.Unmarshal(buf.Bytes(), decoded))
+	assert.Equal(t, g, decoded)
+
+	c = NewContainer()
+	c.Provide(&graphMissing{})
+	buf.Reset()
+	assert.NoError(t, c.Graph().WriteDOT(buf))
+	assert.Contains(t, buf.String(), `missing0 [label="unresolved" color=red];`)
+	assert.Contains(t, buf.String(), `n0 -> missing0 [label="Missing (missing)"];`)
+}
@@ -0,0 +1,182 @@
+package injectgo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// NodeChange is a binding provided by the same name or declared type in both graphs,
+// but with different implementation type, kind or label.
+type NodeChange struct {
+	Old Node
+	New Node
+}
+
+// FieldChange is an inject field whose satisfied object changed.
+type FieldChange struct {
+	Object string // object has the field
+	Field  string
+	Tag    string
+	Old    string // empty if field is not exists
+	New    string // empty if field is not exists
+}
+
+// GraphDiff is the difference between two graphs.
+type GraphDiff struct {
+	Added   []Node // bindings only in new graph
+	Removed []Node // bindings only in old graph
+	Changed []NodeChange
+	Fields  []FieldChange
+}
+
+// IsEmpty return true if there is no difference.
+func (d *GraphDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0 && len(d.Fields) == 0
+}
+
+func (d *GraphDiff) String() string {
+	lines := make([]string, 0)
+	for _, n := range d.Added {
+		lines = append(lines, fmt.Sprintf("+ %s", describeNode(n)))
+	}
+	for _, n := range d.Removed {
+		lines = append(lines, fmt.Sprintf("- %s", describeNode(n)))
+	}
+	for _, c := range d.Changed {
+		lines = append(lines, fmt.Sprintf("~ %s => %s", describeNode(c.Old), describeNode(c.New)))
+	}
+	for _, f := range d.Fields {
+		lines = append(lines, fmt.Sprintf("~ %s.%s `inject:\"%s\"`: %s => %s",
+			f.Object, f.Field, f.Tag, orNone(f.Old), orNone(f.New)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "<none>"
+	}
+	return s
+}
+
+// bindingKey identify binding by name, or by kind and declared type if unnamed,
+// so implementation changed behind the same type is a changed binding.
+func bindingKey(n Node) string {
+	if n.Name != "" {
+		return "name=" + n.Name
+	}
+	tp := n.Type
+	if n.Declared != "" {
+		tp = n.Declared
+	}
+	return fmt.Sprintf("%s [%s]", tp, n.Kind)
+}
+
+// graphBindings return not inner nodes by binding key.
+// Unnamed nodes with the same key are distinguished by their order.
+func graphBindings(g *Graph) (map[string]Node, []string) {
+	nodes := make(map[string]Node)
+	keys := make([]string, 0)
+	for _, n := range g.Nodes {
+		if n.Inner {
+			continue
+		}
+		key := bindingKey(n)
+		for i := 1; ; i++ {
+			if _, ok := nodes[key]; !ok {
+				break
+			}
+			key = fmt.Sprintf("%s #%d", bindingKey(n), i+1)
+		}
+		nodes[key] = n
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return nodes, keys
+}
+
+type fieldKey struct {
+	object string
+	field  string
+	tag    string
+}
+
+// graphFieldTargets return description of object satisfied each field.
+func graphFieldTargets(g *Graph) (map[fieldKey]string, []fieldKey) {
+	targets := make(map[fieldKey]string)
+	keys := make([]fieldKey, 0)
+	for _, e := range g.Edges {
+		from, _ := g.Node(e.From)
+		if from.Inner {
+			continue
+		}
+		k := fieldKey{object: describeNode(from), field: e.Field, tag: e.Tag}
+		to := "<unresolved>"
+		if n, ok := g.Node(e.To); ok {
+			to = describeNode(n)
+		}
+		if _, ok := targets[k]; !ok {
+			keys = append(keys, k)
+		}
+		targets[k] = to
+	}
+	return targets, keys
+}
+
+// Diff return added and removed bindings, bindings changed behind the same name or declared type,
+// and inject fields whose satisfied object changed from graph a to graph b.
+// Use it to compare containers populated with different label selectors or from different releases.
+func Diff(a, b *Graph) *GraphDiff {
+	d := &GraphDiff{
+		Added:   make([]Node, 0),
+		Removed: make([]Node, 0),
+		Changed: make([]NodeChange, 0),
+		Fields:  make([]FieldChange, 0),
+	}
+
+	oldNodes, oldKeys := graphBindings(a)
+	newNodes, newKeys := graphBindings(b)
+	for _, k := range oldKeys {
+		o := oldNodes[k]
+		n, ok := newNodes[k]
+		if !ok {
+			d.Removed = append(d.Removed, o)
+			continue
+		}
+		if o.Type != n.Type || o.Kind != n.Kind || o.Label != n.Label {
+			d.Changed = append(d.Changed, NodeChange{Old: o, New: n})
+		}
+	}
+	for _, k := range newKeys {
+		if _, ok := oldNodes[k]; !ok {
+			d.Added = append(d.Added, newNodes[k])
+		}
+	}
+
+	oldFields, oldFieldKeys := graphFieldTargets(a)
+	newFields, newFieldKeys := graphFieldTargets(b)
+	keys := append([]fieldKey{}, oldFieldKeys...)
+	for _, k := range newFieldKeys {
+		if _, ok := oldFields[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	for _, k := range keys {
+		if oldFields[k] == newFields[k] {
+			continue
+		}
+		d.Fields = append(d.Fields, FieldChange{
+			Object: k.object, Field: k.field, Tag: k.tag,
+			Old: oldFields[k], New: newFields[k],
+		})
+	}
+	sort.Slice(d.Fields, func(i, j int) bool {
+		fi, fj := d.Fields[i], d.Fields[j]
+		if fi.Object != fj.Object {
+			return fi.Object < fj.Object
+		}
+		return fi.Field < fj.Field
+	})
+	return d
+}
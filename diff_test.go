@@ -0,0 +1,90 @@
+package injectgo
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newDiffContainer(labels ...string) *Container {
+	c := NewContainer()
+	c.Provide(&graphService{})
+	c.ProvideWithLabel("prod", &graphClient{Addr: "prod"})
+	c.ProvideWithLabel("dev", &graphClient{Addr: "dev"})
+	c.ProvideFuncByName("named", InjectFunc{
+		Fn:    func() *graphClient { return &graphClient{Addr: "named prod"} },
+		Label: "prod",
+	})
+	c.ProvideFunc(InjectFunc{
+		Fn:    func() fmt.Stringer { return &Person{Name: "prod"} },
+		Label: "prod",
+	}, InjectFunc{
+		Fn:    func() fmt.Stringer { return &ADesc{} },
+		Label: "dev",
+	})
+	if len(labels) > 0 && labels[0] == "dev" {
+		c.ProvideByName("named", &graphClient{Addr: "named dev"})
+	}
+	c.Populate(labelSelector{labels: labels})
+	return c
+}
+
+func TestDiff(t *testing.T) {
+	prod := newDiffContainer("prod")
+	dev := newDiffContainer("dev")
+
+	d := Diff(prod.Graph(), prod.Graph())
+	assert.True(t, d.IsEmpty())
+
+	d = Diff(prod.Graph(), dev.Graph())
+	assert.False(t, d.IsEmpty())
+	t.Log("\n" + d.String())
+
+	assert.Empty(t, d.Added)
+	assert.Empty(t, d.Removed)
+
+	assert.Len(t, d.Changed, 3)
+	assert.Equal(t, "prod", d.Changed[0].Old.Label)
+	assert.Equal(t, "dev", d.Changed[0].New.Label)
+	assert.Equal(t, "*injectgo.Person", d.Changed[1].Old.Type)
+	assert.Equal(t, "*injectgo.ADesc", d.Changed[1].New.Type)
+	assert.Equal(t, "fmt.Stringer", d.Changed[1].New.Declared)
+	assert.Equal(t, "named", d.Changed[2].Old.Name)
+	assert.Equal(t, NodeFunction, d.Changed[2].Old.Kind)
+	assert.Equal(t, NodeObject, d.Changed[2].New.Kind)
+
+	assert.Len(t, d.Fields, 3)
+	assert.Equal(t, "Client", d.Fields[0].Field)
+	assert.Equal(t, "*injectgo.graphClient label=prod", d.Fields[0].Old)
+	assert.Equal(t, "*injectgo.graphClient label=dev", d.Fields[0].New)
+	assert.Equal(t, "named", d.Fields[1].Tag)
+	assert.Equal(t, "Stringer", d.Fields[2].Field)
+	assert.Equal(t, "*injectgo.ADesc label=dev [function]", d.Fields[2].New)
+
+	// declared graphs can be compared as well
+	d = Diff(NewContainer().Graph(), NewContainer().Graph())
+	assert.True(t, d.IsEmpty())
+}
+
+func TestDiff_Implementation(t *testing.T) {
+	newContainer := func(s fmt.Stringer) *Container {
+		c := NewContainer()
+		c.Provide(&graphService{}, &graphClient{})
+		c.ProvideByName("named", &graphClient{})
+		c.ProvideFunc(InjectFunc{Fn: func() fmt.Stringer { return s }, Label: "prod"})
+		c.Populate(nil)
+		return c
+	}
+
+	d := Diff(newContainer(&Person{}).Graph(), newContainer(&ADesc{}).Graph())
+	t.Log("\n" + d.String())
+	assert.Empty(t, d.Added)
+	assert.Empty(t, d.Removed)
+	assert.Len(t, d.Changed, 1)
+	assert.Equal(t, "*injectgo.Person", d.Changed[0].Old.Type)
+	assert.Equal(t, "*injectgo.ADesc", d.Changed[0].New.Type)
+	assert.Len(t, d.Fields, 1)
+	assert.Equal(t, "Stringer", d.Fields[0].Field)
+	assert.Equal(t, "*injectgo.ADesc label=prod [function]", d.Fields[0].New)
+}
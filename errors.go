@@ -1,8 +1,53 @@
 package injectgo
 
-import "fmt"
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrFrozen is returned or panicked by mutating calls if container is frozen.
+var ErrFrozen = fmt.Errorf("container is frozen")
 
 var (
 	errValueNotPtrOrInterface = fmt.Errorf("value should be pointer to struct or interface")
 	errValueNotFunction       = fmt.Errorf("value should be function")
+	errNothingReplaced        = fmt.Errorf("no binding is replaced")
+	errPopulating             = fmt.Errorf("container is populating")
+	errNotPopulated           = fmt.Errorf("container is not populated")
+	errNotSwappable           = fmt.Errorf("no Ref is provided")
+	errClosed                 = fmt.Errorf("container is closed")
 )
+
+// UnfulfilledError is panicked by Populate if some inject fields have no matching object.
+type UnfulfilledError struct {
+	Named   map[string]reflect.Value       // unfulfilled name -> object has the inject field
+	Unnamed map[reflect.Type]reflect.Value // unfulfilled field type -> object has the inject field
+}
+
+func (e *UnfulfilledError) Error() string {
+	return fmt.Sprintf("named unfulfilled objects: %v, unnamed unfulfilled objects: %s",
+		(namedValueMap)(e.Named).prettify(), (fieldValueMap)(e.Unnamed).prettify())
+}
+
+// CyclicError is panicked by Populate if dependency cyclic exists.
+type CyclicError struct {
+	Path []reflect.Type // eg. [t1, t2, t3, t1]
+}
+
+func (e *CyclicError) Error() string {
+	return fmt.Sprintf("dependency cyclic detected, cyclic path %s", (depPath)(e.Path).prettify())
+}
+
+// VisibilityError is panicked by Populate if private bindings of modules are injected into objects of other modules.
+type VisibilityError struct {
+	Violations []Violation
+}
+
+func (e *VisibilityError) Error() string {
+	s := make([]string, 0, len(e.Violations))
+	for _, v := range e.Violations {
+		s = append(s, v.String())
+	}
+	return fmt.Sprintf("private binding injected into other module: %s", strings.Join(s, "; "))
+}
@@ -0,0 +1,181 @@
+package injectgo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	factoriesLock sync.Mutex
+	factories     = make(map[string]reflect.Value) // constructors registered by RegisterFactory
+)
+
+// RegisterFactory registers constructor fn by name, which is instantiated by LoadWiring. Supported fn types:
+//   - func() T
+//   - func() (T, error)
+//   - func(C) T
+//   - func(C) (T, error)
+//
+// T should be pointer to struct or interface. C is decoded from config of each instance in JSON,
+// fields not in C are rejected. It panics if fn is unsupported or name is duplicate.
+func RegisterFactory(name string, fn interface{}) {
+	v := reflect.ValueOf(fn)
+	if !v.IsValid() || v.Kind() != reflect.Func {
+		panic(fmt.Errorf("factory %s error: %v", name, errValueNotFunction))
+	}
+	t := v.Type()
+	if t.NumIn() > 1 || t.IsVariadic() {
+		panic(fmt.Errorf("factory %s should accept at most 1 argument", name))
+	}
+	if t.NumOut() <= 0 || t.NumOut() > 2 {
+		panic(fmt.Errorf("factory %s should be at most 2 return values", name))
+	}
+	if t.NumOut() == 2 && t.Out(1) != reflect.TypeOf((*error)(nil)).Elem() {
+		panic(fmt.Errorf("factory %s second return value should be error", name))
+	}
+	if out := t.Out(0); out.Kind() != reflect.Interface && (out.Kind() != reflect.Ptr || out.Elem().Kind() != reflect.Struct) {
+		panic(fmt.Errorf("factory %s error: %v", name, errValueNotPtrOrInterface))
+	}
+
+	factoriesLock.Lock()
+	defer factoriesLock.Unlock()
+	if _, ok := factories[name]; ok {
+		panic(fmt.Errorf("duplicate factory name: %s", name))
+	}
+	factories[name] = v
+}
+
+// Factories return names of registered factories in sorted order.
+func Factories() []string {
+	factoriesLock.Lock()
+	defer factoriesLock.Unlock()
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// WiringConfig is the JSON document read by LoadWiring.
+type WiringConfig struct {
+	Bindings []FactoryBinding `json:"bindings"`
+}
+
+// FactoryBinding is an instance of factory.
+type FactoryBinding struct {
+	Factory string          `json:"factory"`
+	Name    string          `json:"name,omitempty"`   // provided by ProvideFuncByName if not empty
+	Label   string          `json:"label,omitempty"`  // label of provided function
+	Config  json.RawMessage `json:"config,omitempty"` // argument of factory
+}
+
+// LoadWiring read WiringConfig in JSON from r, and provide a function for every binding,
+// which calls the factory with its config when Populate.
+// All bindings are checked before any is provided, returned error reports every unknown factory, bad config
+// and name already used by the document or bindings of c.
+func (c *Container) LoadWiring(r io.Reader) error {
+	site := callerSite(1)
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+	var cfg WiringConfig
+	if err := dec.Decode(&cfg); err != nil {
+		return fmt.Errorf("load wiring error: %v", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.mutableError(); err != nil {
+		return fmt.Errorf("load wiring error: %w", err)
+	}
+
+	funcs := make([]InjectFunc, 0, len(cfg.Bindings))
+	problems := make([]string, 0)
+	names := make(map[string]bool)
+	for i, b := range cfg.Bindings {
+		ifn, err := b.injectFunc()
+		if err == nil && b.Name != "" {
+			err = c.checkWiringName(b.Name, names)
+		}
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("binding %d (factory %s): %v", i, b.Factory, err))
+			continue
+		}
+		names[b.Name] = true
+		funcs = append(funcs, ifn)
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("load wiring error:\n%s", strings.Join(problems, "\n"))
+	}
+
+	for i, b := range cfg.Bindings {
+		ifn := funcs[i]
+		ifn.site = site
+		ifn.order = c.nextFuncOrder()
+		if b.Name == "" {
+			c.unnamedFunctions = append(c.unnamedFunctions, ifn)
+			continue
+		}
+		c.namedFunctions[b.Name] = ifn
+		c.namedFuncOrder = append(c.namedFuncOrder, b.Name)
+	}
+	return nil
+}
+
+// checkWiringName return error if name is reserved, used by other bindings of document or provided to c.
+func (c *Container) checkWiringName(name string, names map[string]bool) error {
+	_, function := c.namedFunctions[name]
+	_, object := c.namedValues[name]
+	switch {
+	case name == privateTag:
+		return fmt.Errorf("name %s is reserved", name)
+	case names[name] || function || object:
+		return fmt.Errorf("duplicate name: %s", name)
+	}
+	return nil
+}
+
+// injectFunc return function calling factory of b with config decoded from b.
+func (b FactoryBinding) injectFunc() (InjectFunc, error) {
+	factoriesLock.Lock()
+	fn, ok := factories[b.Factory]
+	factoriesLock.Unlock()
+	if !ok {
+		return InjectFunc{}, fmt.Errorf("unknown factory")
+	}
+
+	t := fn.Type()
+	args := make([]reflect.Value, 0, 1)
+	if t.NumIn() == 1 {
+		arg := reflect.New(t.In(0))
+		if len(b.Config) > 0 {
+			dec := json.NewDecoder(bytes.NewReader(b.Config))
+			dec.DisallowUnknownFields()
+			if err := dec.Decode(arg.Interface()); err != nil {
+				return InjectFunc{}, fmt.Errorf("bad config: %v", err)
+			}
+		}
+		if arg.Elem().Kind() == reflect.Ptr && arg.Elem().IsNil() {
+			arg.Elem().Set(reflect.New(t.In(0).Elem()))
+		}
+		args = append(args, arg.Elem())
+	} else if len(b.Config) > 0 && string(b.Config) != "null" {
+		return InjectFunc{}, fmt.Errorf("bad config: factory accepts no config")
+	}
+
+	outs := []reflect.Type{t.Out(0), reflect.TypeOf((*error)(nil)).Elem()}
+	create := reflect.MakeFunc(reflect.FuncOf(nil, outs, false), func([]reflect.Value) []reflect.Value {
+		ret := fn.Call(args)
+		if len(ret) == 1 {
+			ret = append(ret, reflect.Zero(outs[1]))
+		}
+		return ret
+	})
+	return InjectFunc{Fn: create.Interface(), Label: b.Label}, nil
+}
@@ -0,0 +1,129 @@
+package injectgo
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type factoryCache struct {
+	Addr string
+	Size int
+}
+
+type factoryCacheConfig struct {
+	Addr string `json:"addr"`
+	Size int    `json:"size"`
+}
+
+type factoryService struct {
+	Cache  *factoryCache `inject:"cache"`
+	Memory *factoryCache `inject:""`
+}
+
+func resetFactories() {
+	factoriesLock.Lock()
+	defer factoriesLock.Unlock()
+	factories = make(map[string]reflect.Value)
+}
+
+func TestLoadWiring(t *testing.T) {
+	resetFactories()
+	defer resetFactories()
+
+	RegisterFactory("redis-cache", func(cfg factoryCacheConfig) (*factoryCache, error) {
+		if cfg.Addr == "" {
+			return nil, fmt.Errorf("addr is empty")
+		}
+		return &factoryCache{Addr: cfg.Addr, Size: cfg.Size}, nil
+	})
+	RegisterFactory("memory-cache", func() *factoryCache { return &factoryCache{Addr: "memory"} })
+	assert.Equal(t, []string{"memory-cache", "redis-cache"}, Factories())
+	assert.Panics(t, func() {
+		RegisterFactory("memory-cache", func() *factoryCache { return nil })
+	}, "should panic because name is duplicate")
+	assert.Panics(t, func() {
+		RegisterFactory("bad", func(a, b int) *factoryCache { return nil })
+	}, "should panic because factory accepts 2 arguments")
+	assert.Panics(t, func() {
+		RegisterFactory("bad", func() factoryCache { return factoryCache{} })
+	}, "should panic because factory returns struct")
+	assert.Panics(t, func() {
+		RegisterFactory("bad", func() (int, error) { return 0, nil })
+	}, "should panic because factory returns int")
+
+	c := NewContainer()
+	s := &factoryService{}
+	c.Provide(s)
+	err := c.LoadWiring(strings.NewReader(`{"bindings": [
+		{"factory": "redis-cache", "name": "cache", "config": {"addr": "10.0.0.1:6379", "size": 10}},
+		{"factory": "memory-cache", "label": "dev"},
+		{"factory": "redis-cache", "label": "prod", "config": {"addr": "10.0.0.2:6379"}}
+	]}`))
+	assert.NoError(t, err)
+	c.Populate(moduleSelector{"prod": true})
+	assert.Equal(t, &factoryCache{Addr: "10.0.0.1:6379", Size: 10}, s.Cache)
+	assert.Equal(t, &factoryCache{Addr: "10.0.0.2:6379"}, s.Memory)
+
+	c = NewContainer()
+	c.Provide(&factoryService{})
+	assert.NoError(t, c.LoadWiring(strings.NewReader(`{"bindings": [{"factory": "redis-cache"}]}`)))
+	assert.Panics(t, func() {
+		c.Populate(nil)
+	}, "should panic because factory returns error")
+}
+
+func TestLoadWiring_Error(t *testing.T) {
+	resetFactories()
+	defer resetFactories()
+	RegisterFactory("redis-cache", func(cfg *factoryCacheConfig) *factoryCache { return &factoryCache{} })
+	RegisterFactory("memory-cache", func() *factoryCache { return &factoryCache{} })
+
+	c := NewContainer()
+	err := c.LoadWiring(strings.NewReader(`{"bindings": [
+		{"factory": "redis-cache", "name": "cache", "config": {"addr": "10.0.0.1:6379"}},
+		{"factory": "file-cache"},
+		{"factory": "redis-cache", "config": {"address": "10.0.0.1:6379"}},
+		{"factory": "redis-cache", "config": {"size": "big"}},
+		{"factory": "memory-cache", "config": {"size": 1}},
+		{"factory": "memory-cache", "name": "cache"}
+	]}`))
+	assert.Error(t, err)
+	lines := strings.Split(err.Error(), "\n")
+	assert.Len(t, lines, 6)
+	assert.Equal(t, "load wiring error:", lines[0])
+	assert.Equal(t, "binding 1 (factory file-cache): unknown factory", lines[1])
+	assert.Equal(t, `binding 2 (factory redis-cache): bad config: json: unknown field "address"`, lines[2])
+	assert.Contains(t, lines[3], "binding 3 (factory redis-cache): bad config: json: cannot unmarshal string")
+	assert.Equal(t, "binding 4 (factory memory-cache): bad config: factory accepts no config", lines[4])
+	assert.Equal(t, "binding 5 (factory memory-cache): duplicate name: cache", lines[5])
+	assert.Empty(t, c.Graph().Nodes, "nothing should be provided if any binding is bad")
+
+	err = c.LoadWiring(strings.NewReader(`{"binding": []}`))
+	assert.EqualError(t, err, `load wiring error: json: unknown field "binding"`)
+
+	/// test names provided to container are checked before anything is provided
+	c.ProvideByName("cache", &factoryCache{})
+	c.ProvideFuncByName("local", InjectFunc{Fn: func() *factoryCache { return &factoryCache{} }})
+	err = c.LoadWiring(strings.NewReader(`{"bindings": [
+		{"factory": "memory-cache", "name": "memory"},
+		{"factory": "memory-cache", "name": "cache"},
+		{"factory": "memory-cache", "name": "local"},
+		{"factory": "memory-cache", "name": "private"}
+	]}`))
+	assert.EqualError(t, err, "load wiring error:\n"+
+		"binding 1 (factory memory-cache): duplicate name: cache\n"+
+		"binding 2 (factory memory-cache): duplicate name: local\n"+
+		"binding 3 (factory memory-cache): name private is reserved")
+	assert.Len(t, c.Graph().Nodes, 2, "nothing should be provided if any name is used")
+	assert.NoError(t, c.LoadWiring(strings.NewReader(`{"bindings": [{"factory": "memory-cache", "name": "memory"}]}`)))
+	assert.Len(t, c.Graph().Nodes, 3)
+
+	c.Freeze()
+	err = c.LoadWiring(strings.NewReader(`{"bindings": [{"factory": "memory-cache"}]}`))
+	assert.True(t, errors.Is(err, ErrFrozen))
+}
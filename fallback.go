@@ -0,0 +1,97 @@
+package injectgo
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// FallbackFunc return object for inject field of type tp left unfulfilled by provided bindings,
+// name is the inject name of the field or empty for unnamed field. Return false if it can not provide one.
+type FallbackFunc func(name string, tp reflect.Type) (interface{}, bool)
+
+// Fallback adds fn called by Populate for each inject field left unfulfilled after functions are called,
+// objects it returns are selected like provided objects before fields are checked.
+// Fallbacks are called in added order until one returns an object.
+func (c *Container) Fallback(fn FallbackFunc) {
+	if fn == nil {
+		panic(errValueNotFunction)
+	}
+
+	c.lockRegistering()
+	defer c.mu.Unlock()
+	c.fallbacks = append(c.fallbacks, fn)
+}
+
+// selectFallbackObjects select objects returned by fallbacks for unfulfilled fields,
+// until all fields are fulfilled or no fallback returns object.
+func (c *Container) selectFallbackObjects() {
+	for len(c.fallbacks) > 0 && !c.checker.isAllFulfilled() {
+		selected := false
+		named := c.checker.getUnfulfilledNamedValues()
+		names := make([]string, 0, len(named))
+		for name := range named {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			tp := namedFieldType(named[name], name)
+			if tp == nil {
+				// field type unknown, the name is left unfulfilled and reported by Populate
+				continue
+			}
+			if v, ok := c.fallback(name, tp); ok {
+				c.selectNamedValue(name, v, binding{name: name, kind: NodeObject})
+				selected = true
+			}
+		}
+
+		unnamed := c.checker.getUnfulfilledUnnamedValues()
+		types := make([]reflect.Type, 0, len(unnamed))
+		for tp := range unnamed {
+			types = append(types, tp)
+		}
+		sort.Slice(types, func(i, j int) bool { return types[i].String() < types[j].String() })
+		for _, tp := range types {
+			if v, ok := c.fallback("", tp); ok {
+				c.selectUnnamedValue(v, binding{kind: NodeObject})
+				selected = true
+			}
+		}
+
+		if !selected {
+			return
+		}
+		c.checker.popRemainedValues()
+	}
+}
+
+// fallback return object of the first fallback providing one for field of type tp.
+func (c *Container) fallback(name string, tp reflect.Type) (reflect.Value, bool) {
+	for _, fn := range c.fallbacks {
+		obj, ok := fn(name, tp)
+		if !ok {
+			continue
+		}
+		v := reflect.ValueOf(obj)
+		if !v.IsValid() || !c.isStructPtrOrInterface(v) || !v.Type().AssignableTo(tp) {
+			panic(fmt.Errorf("fallback object %T of field %q error: not assignable to %v", obj, name, tp))
+		}
+		return v, true
+	}
+	return reflect.Value{}, false
+}
+
+// namedFieldType return type of field of obj injected by name, nil if obj is not struct or has no such field.
+func namedFieldType(obj reflect.Value, name string) reflect.Type {
+	t := reflect.Indirect(obj).Type()
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get(injectTag) == name {
+			return t.Field(i).Type
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package injectgo
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainer_Fallback(t *testing.T) {
+	type B struct {
+		Name string
+	}
+	type A struct {
+		B        *B           `inject:""`
+		Stringer fmt.Stringer `inject:"stringer"`
+		Other    fmt.Stringer `inject:"other"`
+	}
+
+	var calls []string
+	c := NewContainer()
+	c.Fallback(func(name string, tp reflect.Type) (interface{}, bool) {
+		calls = append(calls, fmt.Sprintf("%s %v", name, tp))
+		switch tp {
+		case reflect.TypeOf((*B)(nil)):
+			return &B{Name: "fallback"}, true
+		case reflect.TypeOf((*fmt.Stringer)(nil)).Elem():
+			return &Person{Name: name}, true
+		}
+		return nil, false
+	})
+	a := &A{}
+	c.Provide(a)
+	c.ProvideByName("other", &Person{Name: "provided"})
+	c.Populate(nil)
+
+	assert.Equal(t, "fallback", a.B.Name)
+	assert.Equal(t, "name:stringer", a.Stringer.String())
+	assert.Equal(t, "name:provided", a.Other.String())
+	assert.Equal(t, []string{"stringer fmt.Stringer", " *injectgo.B"}, calls)
+
+	c = NewContainer()
+	c.Fallback(func(name string, tp reflect.Type) (interface{}, bool) {
+		return nil, false
+	})
+	c.Provide(&A{})
+	assert.Panics(t, func() {
+		c.Populate(nil)
+	})
+
+	c = NewContainer()
+	c.Fallback(func(name string, tp reflect.Type) (interface{}, bool) {
+		return &B{}, true
+	})
+	c.Provide(&struct {
+		Stringer fmt.Stringer `inject:""`
+	}{})
+	assert.Panics(t, func() {
+		c.Populate(nil)
+	})
+}
+
+func TestContainer_FallbackUnknownFieldType(t *testing.T) {
+	assert.Nil(t, namedFieldType(reflect.ValueOf(&B{}), "missing"))
+
+	calls := 0
+	c := NewContainer()
+	c.Fallback(func(name string, tp reflect.Type) (interface{}, bool) {
+		calls++
+		return &B{}, true
+	})
+	// object recorded for the name does not declare the field
+	c.checker.unfulfilledNamedValues["missing"] = reflect.ValueOf(&B{})
+	assert.NotPanics(t, func() {
+		c.selectFallbackObjects()
+	})
+	assert.Equal(t, 0, calls, "fallback should not be called for field of unknown type")
+	assert.Contains(t, c.checker.getUnfulfilledNamedValues(), "missing")
+}
@@ -2,6 +2,7 @@ package injectgo
 
 import (
 	"fmt"
+	"maps"
 	"reflect"
 	"strings"
 )
@@ -42,6 +43,9 @@ func scanInjectFields(v reflect.Value) []injectField {
 	ret := make([]injectField, 0)
 	rawV := reflect.Indirect(v)
 	t := rawV.Type()
+	if t.Kind() != reflect.Struct {
+		return ret
+	}
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
 		if inj, ok := field.Tag.Lookup(injectTag); ok {
@@ -95,15 +99,27 @@ func (o *injectObject) SetField(v reflect.Value, field *injectField) {
 }
 
 type objectGraph struct {
+	parent *objectGraph // populated graph of parent container, fulfills fields not fulfilled by g
+
 	unnamedObjects map[reflect.Type]*injectObject
 	namedObjects   map[string]*injectObject
 
 	fulfilledUnnamedObjects map[reflect.Type]*injectObject
 	fulfilledNamedObjects   map[string]*injectObject
+	resolvedObjects         map[reflect.Type]*injectObject // objects resolved for unnamed fields since snapshot
+
+	innerObjects []*injectObject // objects populated but not matched by any field
+
+	unnamedTransients map[reflect.Type]*transient
+	namedTransients   map[string]*transient
+	transientObjects  []transientObject // objects created by transients and for private fields
 
 	addedObjectsPtr map[uintptr]bool
 	initObjects     []Initializable // objects need to be initialized
+	initCloses      []int           // number of closeObjects added before each of initObjects
+	initialized     int             // number of initObjects initialized
 	closeObjects    []Closable      // objects need to be closed
+	closesReady     int             // number of closeObjects added before last initAllObjects succeeded
 }
 
 func newObjectGraph() *objectGraph {
@@ -112,6 +128,10 @@ func newObjectGraph() *objectGraph {
 		namedObjects:            map[string]*injectObject{},
 		fulfilledUnnamedObjects: map[reflect.Type]*injectObject{},
 		fulfilledNamedObjects:   map[string]*injectObject{},
+		resolvedObjects:         map[reflect.Type]*injectObject{},
+		innerObjects:            make([]*injectObject, 0),
+		unnamedTransients:       map[reflect.Type]*transient{},
+		namedTransients:         map[string]*transient{},
 		initObjects:             make([]Initializable, 0),
 		closeObjects:            make([]Closable, 0),
 		addedObjectsPtr:         make(map[uintptr]bool),
@@ -135,6 +155,7 @@ func (g *objectGraph) addObjectCall(obj *injectObject) {
 	i := obj.value.Interface()
 	if initObj, ok := i.(Initializable); ok {
 		g.initObjects = append(g.initObjects, initObj)
+		g.initCloses = append(g.initCloses, len(g.closeObjects))
 	}
 	if closeObj, ok := i.(Closable); ok {
 		g.closeObjects = append(g.closeObjects, closeObj)
@@ -161,20 +182,129 @@ func (g *objectGraph) ProvideNamedObj(name string, obj reflect.Value) {
 	}
 }
 
+// ProvideInnerObj add obj which is populated, initialized and closed,
+// but never injected into other objects, eg. object wrapped by decorator.
+func (g *objectGraph) ProvideInnerObj(obj reflect.Value) {
+	g.innerObjects = append(g.innerObjects, newInjectObject(obj))
+}
+
+// fulfillObject set inject fields of v with populated objects, v is not added to g.
+// It returns error without setting any field if some field has no matching populated object.
+func (g *objectGraph) fulfillObject(v reflect.Value) error {
+	obj := newInjectObject(v)
+	fields := obj.UnfulfilledFields()
+	matched := make([]*injectObject, len(fields))
+	for i := range fields {
+		matched[i] = g.findExistingObject(&fields[i])
+		if matched[i] == nil || !matched[i].isComplete {
+			return fmt.Errorf("field (%s) of %s has no matching object", fields[i].fieldType, v)
+		}
+	}
+	for i := range fields {
+		obj.SetField(matched[i].value, &fields[i])
+	}
+	return nil
+}
+
+// replaceObjectCall replace old with obj in objects to be closed, obj is considered initialized.
+func (g *objectGraph) replaceObjectCall(old, obj reflect.Value) {
+	closeObj, closable := obj.Interface().(Closable)
+	for i := range g.closeObjects {
+		if isSameObject(reflect.ValueOf(g.closeObjects[i]), old) {
+			if closable {
+				g.closeObjects[i] = closeObj
+			} else {
+				g.closeObjects = append(g.closeObjects[:i], g.closeObjects[i+1:]...)
+				if i < g.closesReady {
+					g.closesReady--
+				}
+			}
+			return
+		}
+	}
+	if closable {
+		g.closeObjects = append(g.closeObjects, closeObj)
+		g.closesReady++
+	}
+}
+
+// snapshot return a copy of g before objects of a Populate are provided, restore reverts g to it.
+// Objects resolved for unnamed fields are reset.
+func (g *objectGraph) snapshot() *objectGraph {
+	g.resolvedObjects = map[reflect.Type]*injectObject{}
+	s := *g
+	s.unnamedObjects = maps.Clone(g.unnamedObjects)
+	s.namedObjects = maps.Clone(g.namedObjects)
+	s.fulfilledUnnamedObjects = maps.Clone(g.fulfilledUnnamedObjects)
+	s.fulfilledNamedObjects = maps.Clone(g.fulfilledNamedObjects)
+	s.unnamedTransients = maps.Clone(g.unnamedTransients)
+	s.namedTransients = maps.Clone(g.namedTransients)
+	s.addedObjectsPtr = maps.Clone(g.addedObjectsPtr)
+	return &s
+}
+
+func (g *objectGraph) restore(s *objectGraph) {
+	*g = *s
+	g.resolvedObjects = map[reflect.Type]*injectObject{}
+}
+
+// findMatchingObject return provided object matching field, or new object created by transient function
+// or for private field.
 func (g *objectGraph) findMatchingObject(field *injectField) *injectObject {
 	if field.isSatisfied {
 		return nil
 	}
-	if field.tagName != "" {
-		if o, ok := g.fulfilledNamedObjects[field.tagName]; ok {
-			return o
-		}
-		if o, ok := g.namedObjects[field.tagName]; ok {
-			return o
-		}
+	if o := g.resolveExistingObject(field); o != nil {
+		return o
+	}
+	if t := g.findTransient(field); t != nil {
+		return g.newTransientObject(t)
+	}
+	if field.tagName == privateTag {
+		return g.newPrivateObject(field)
+	}
+	return nil
+}
+
+// findExistingObject return provided object matching field.
+func (g *objectGraph) findExistingObject(field *injectField) *injectObject {
+	switch field.tagName {
+	case privateTag:
 		return nil
+	case "":
+		return g.findUnnamedObjectByType(field.fieldType)
+	default:
+		return g.findNamedObject(field.tagName)
+	}
+}
+
+// resolveExistingObject works like findExistingObject, but object found for unnamed field is recorded,
+// so all fields of the type resolve to the same object, even if several objects are assignable to it.
+func (g *objectGraph) resolveExistingObject(field *injectField) *injectObject {
+	if field.tagName != "" {
+		return g.findExistingObject(field)
+	}
+	if o, ok := g.resolvedObjects[field.fieldType]; ok {
+		return o
+	}
+	o := g.findUnnamedObjectByType(field.fieldType)
+	if o != nil {
+		g.resolvedObjects[field.fieldType] = o
 	}
-	return g.findUnnamedObjectByType(field.fieldType)
+	return o
+}
+
+func (g *objectGraph) findNamedObject(name string) *injectObject {
+	if o, ok := g.fulfilledNamedObjects[name]; ok {
+		return o
+	}
+	if o, ok := g.namedObjects[name]; ok {
+		return o
+	}
+	if g.parent != nil {
+		return g.parent.findNamedObject(name)
+	}
+	return nil
 }
 
 func (g *objectGraph) findUnnamedObjectByType(tp reflect.Type) *injectObject {
@@ -194,15 +324,25 @@ func (g *objectGraph) findUnnamedObjectByType(tp reflect.Type) *injectObject {
 			return o
 		}
 	}
+	if g.parent != nil {
+		return g.parent.findUnnamedObjectByType(tp)
+	}
 	return nil
 }
 
 func (g *objectGraph) Populate() {
+	g.populateInnerObjects()
 	g.populateNamedObjects()
 	g.populateUnnamedObjects()
 	g.initAllObjects()
 }
 
+func (g *objectGraph) populateInnerObjects() {
+	for _, injObj := range g.innerObjects {
+		g.populateObject(injObj, 0)
+	}
+}
+
 func (g *objectGraph) populateNamedObjects() {
 	for _, injObj := range g.namedObjects {
 		g.populateObject(injObj, 0)
@@ -242,12 +382,24 @@ func (g *objectGraph) populateObject(obj *injectObject, depth int) {
 	panic(fmt.Errorf("object %s not complete", obj))
 }
 
+// initAllObjects initialize objects not initialized by previous Populate in order.
+// If Init fails, it closes objects of this Populate added before the failed object in inverse order
+// and panics like code generated by injectgo gen, these objects are initialized again by next Populate.
 func (g *objectGraph) initAllObjects() {
-	for i := range g.initObjects {
-		if err := g.initObjects[i].Init(); err != nil {
+	start := g.initialized
+	for g.initialized < len(g.initObjects) {
+		obj := g.initObjects[g.initialized]
+		if err := obj.Init(); err != nil {
+			for i := g.initCloses[g.initialized] - 1; i >= g.closesReady; i-- {
+				// error is dropped, Init error is reported
+				g.closeObjects[i].Close()
+			}
+			g.initialized = start
 			panic(err)
 		}
+		g.initialized++
 	}
+	g.closesReady = len(g.closeObjects)
 }
 
 func (g *objectGraph) Close() {
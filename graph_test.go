@@ -2,6 +2,7 @@ package injectgo
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"github.com/stretchr/testify/assert"
 	"log"
@@ -147,3 +148,50 @@ func TestObjectGraph_InitClose(t *testing.T) {
 
 	t.Log(a, b, c)
 }
+
+type structFailing struct {
+	cnt  initCloseCounter
+	fail bool
+	B    *structB `inject:""`
+}
+
+func (f *structFailing) Init() error {
+	if f.fail {
+		return errors.New("init failed")
+	}
+	f.cnt.initCnt++
+	return nil
+}
+
+func (f *structFailing) Close() error {
+	f.cnt.closeCnt++
+	return nil
+}
+
+func TestObjectGraph_InitFailed(t *testing.T) {
+	g := newObjectGraph()
+	c := &structC{}
+	g.ProvideObj(reflect.ValueOf(c))
+	g.Populate()
+
+	b := &structB{}
+	f := &structFailing{fail: true}
+	g.ProvideObj(reflect.ValueOf(b))
+	g.ProvideObj(reflect.ValueOf(f))
+	assert.Panics(t, func() {
+		g.Populate()
+	})
+	assert.Equal(t, initCloseCounter{initCnt: 1, closeCnt: 1}, b.cnt, "object initialized before failed one should be closed")
+	assert.Equal(t, initCloseCounter{}, f.cnt)
+	assert.Equal(t, initCloseCounter{initCnt: 1}, c.cnt, "object of previous Populate should not be closed")
+
+	f.fail = false
+	g.initAllObjects()
+	assert.Equal(t, 2, b.cnt.initCnt)
+	assert.Equal(t, 1, f.cnt.initCnt, "failed object should be initialized again")
+	assert.Equal(t, 1, c.cnt.initCnt)
+
+	g.Close()
+	assert.Equal(t, 1, f.cnt.closeCnt)
+	assert.Equal(t, 1, c.cnt.closeCnt)
+}
@@ -0,0 +1,62 @@
+// Package httpinject injects request scoped objects into net/http handlers.
+package httpinject
+
+import (
+	"net/http"
+
+	"github.com/RivenZoo/injectgo"
+)
+
+// Middleware return a middleware opening a scope of c for every request.
+// *http.Request and http.ResponseWriter of the request are provided to the scope before it is populated,
+// the request passed to next carries the scope in its context, and the scope is closed after next returns.
+// Like Populate, it panics if the scope can not be populated.
+func Middleware(c *injectgo.Container) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			s, r := openScope(c, w, r)
+			defer s.Close()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func openScope(c *injectgo.Container, w http.ResponseWriter, r *http.Request) (*injectgo.Container, *http.Request) {
+	s := c.NewScope()
+	r = r.WithContext(injectgo.WithScope(r.Context(), s))
+	s.Provide(r, w)
+	s.Populate(nil)
+	return s, r
+}
+
+// Handler return a http.Handler serving every request by a new T, whose inject fields are populated
+// in a child of scope of the request, and T is closed after it serves. Scope opened by Middleware is used
+// if request carries one, otherwise a scope of c is opened and closed for the request.
+//
+//	type UserHandler struct {
+//		Store Store         `inject:""`
+//		Req   *http.Request `inject:""`
+//	}
+//
+//	func (h *UserHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {}
+//
+//	mux.Handle("/user", httpinject.Handler[UserHandler](c))
+func Handler[T any, PT interface {
+	*T
+	http.Handler
+}](c *injectgo.Container) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s, ok := injectgo.FromContext(r.Context())
+		if !ok {
+			s, r = openScope(c, w, r)
+			defer s.Close()
+		}
+		// populate handler in its own child, so handlers in one chain do not share the scope bindings
+		hs := s.NewScope()
+		defer hs.Close()
+		h := PT(new(T))
+		hs.Provide(h)
+		hs.Populate(nil)
+		h.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,105 @@
+package httpinject
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/RivenZoo/injectgo"
+	"github.com/stretchr/testify/assert"
+)
+
+type greeting struct {
+	Text string
+}
+
+type session struct {
+	Req    *http.Request `inject:""`
+	closed bool
+}
+
+func (s *session) Close() error {
+	s.closed = true
+	return nil
+}
+
+type greetHandler struct {
+	Greeting *greeting           `inject:""`
+	Session  *session            `inject:""`
+	W        http.ResponseWriter `inject:""`
+}
+
+var sessions []*session
+
+func (h *greetHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	sessions = append(sessions, h.Session)
+	s, _ := injectgo.FromContext(r.Context())
+	obj, _ := s.LookupType((*session)(nil))
+	fmt.Fprintf(h.W, "%s %s %v", h.Greeting.Text, h.Session.Req.URL.Path, obj == h.Session)
+}
+
+func newContainer() *injectgo.Container {
+	c := injectgo.NewContainer()
+	c.Provide(&greeting{Text: "hello"})
+	c.ProvideFunc(injectgo.InjectFunc{Fn: func() *session { return &session{} }, Scope: injectgo.ScopeRequest})
+	c.Populate(nil)
+	return c
+}
+
+func TestHandler(t *testing.T) {
+	sessions = nil
+	c := newContainer()
+	handlers := map[string]http.Handler{
+		"handler":    Handler[greetHandler](c),
+		"middleware": Middleware(c)(Handler[greetHandler](c)),
+	}
+	for name, h := range handlers {
+		for _, path := range []string{"/a", "/b"} {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+			assert.Equal(t, "hello "+path+" true", w.Body.String(), name)
+		}
+	}
+	assert.Len(t, sessions, 4)
+	for i, s := range sessions {
+		assert.True(t, s.closed, "session should be closed with scope")
+		for _, other := range sessions[:i] {
+			assert.True(t, s != other, "each request should get its own session")
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	c := newContainer()
+	var s *session
+	h := Middleware(c)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scope, ok := injectgo.FromContext(r.Context())
+		assert.True(t, ok)
+		obj, ok := scope.LookupType((*session)(nil))
+		assert.True(t, ok)
+		s = obj.(*session)
+		assert.Equal(t, r, s.Req)
+		assert.False(t, s.closed)
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.True(t, s.closed)
+}
+
+func TestHandler_Chain(t *testing.T) {
+	sessions = nil
+	c := newContainer()
+	first, second := Handler[greetHandler](c), Handler[greetHandler](c)
+	h := Middleware(c)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		first.ServeHTTP(w, r)
+		second.ServeHTTP(w, r)
+		scope, _ := injectgo.FromContext(r.Context())
+		_, ok := scope.LookupType((*greetHandler)(nil))
+		assert.False(t, ok, "handlers should not be provided to scope of middleware")
+	}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/a", nil))
+	assert.Equal(t, "hello /a truehello /a true", w.Body.String())
+	assert.Len(t, sessions, 2)
+	assert.True(t, sessions[0] == sessions[1], "handlers of one request should share its session")
+}
@@ -3,6 +3,9 @@ package injectgo
 import (
 	"fmt"
 	"reflect"
+	"runtime"
+	"sort"
+	"sync"
 )
 
 const injectTag = "inject"
@@ -12,25 +15,122 @@ type FuncLabelSelector interface {
 	IsLabelAllowed(string) bool
 }
 
+// isLabelSelected return true if label is empty, selector is nil or selector allows label.
+func isLabelSelected(labelSelector FuncLabelSelector, label string) bool {
+	return labelSelector == nil || label == "" || labelSelector.IsLabelAllowed(label)
+}
+
+// providedValue is a provided object and the label used to select it.
+type providedValue struct {
+	value   reflect.Value
+	label   string // default selected
+	site    string // file:line where object is provided
+	module  string // name of module installs the object
+	private bool   // only injected into objects of the same module
+
+	populated bool // selected by a previous Populate
+}
+
+// callerSite return file:line of the caller of function which calls callerSite with skip 1.
+func callerSite(skip int) string {
+	_, file, line, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", file, line)
+}
+
+// binding describes where a selected object comes from.
+type binding struct {
+	name    string
+	label   string
+	site    string
+	module  string
+	private bool
+	kind    NodeKind
+}
+
+// source is where bindings are provided.
+type source struct {
+	site    string // file:line of the provide call
+	module  string // name of module installs bindings
+	private bool   // bindings are only injected into objects of the same module
+}
+
+// selectedObject is an object selected in Populate.
+type selectedObject struct {
+	binding
+	value reflect.Value
+	inner bool // wrapped by decorator, never injected
+	wraps int  // index of object wrapped by this object, -1 if not decorated
+}
+
 // Container receive all provided objects and function then inject all of them.
+// It is safe for concurrent use: objects and functions can be provided concurrently before Populate,
+// and populated objects can be looked up concurrently after Populate.
 type Container struct {
+	// mu guards all fields. Populate runs without holding it after setting state to populating,
+	// so functions and Init methods called by Populate can use the container without deadlock.
+	mu     sync.RWMutex
+	state  State
+	frozen bool       // bindings can not be mutated
+	swapMu sync.Mutex // serialize Swap
+
+	graceCloses map[*graceClose]bool // objects swapped out waiting for grace period to close
+	graceWG     sync.WaitGroup       // number of graceCloses not closed yet
+
+	parent           *Container // container creates c by NewScope
 	graph            *objectGraph
-	namedValues      map[string]reflect.Value
-	unnamedValues    []reflect.Value
+	namedValues      map[string]providedValue
+	unnamedValues    []providedValue
 	namedFunctions   map[string]InjectFunc
+	namedFuncOrder   []string // function names in provided order
 	unnamedFunctions []InjectFunc
+	funcOrder        int                       // order of last provided function, both named and unnamed
+	modules          map[string]*Module        // installed modules by name
+	copies           map[uintptr]reflect.Value // copies of provided objects of the container cloned from
 	checker          *injectChecker
 	detector         *cyclicDetector
+	populated        bool
+	populatedObjects int // number of selectedObjects populated by previous Populate
+
+	decorators []decorator
+	fallbacks  []FallbackFunc
+
+	// objects selected by label selector in current Populate
+	selectedNamedValues   map[string]reflect.Value
+	selectedUnnamedValues []reflect.Value
+	selectedInnerValues   []reflect.Value  // objects wrapped by decorators
+	selectedObjects       []selectedObject // objects selected in all Populate
+	selectedTransients    []*transient
+	requestFunctions      []*transient // functions with ScopeRequest selected in all Populate
+
+	// functions whose conditions not match in Populate
+	unmatchedNamedFunctions   map[string]bool
+	unmatchedUnnamedFunctions map[int]bool
+	conditionResults          []ConditionResult
+	functionResults           []functionResult
+}
+
+// functionResult records whether a function is called in Populate.
+type functionResult struct {
+	name     string
+	label    string
+	tp       reflect.Type
+	selected bool
+	reason   string // why function is skipped
 }
 
 // NewContainer
 func NewContainer() (c *Container) {
 	c = &Container{
 		graph:            newObjectGraph(),
-		namedValues:      make(map[string]reflect.Value),
-		unnamedValues:    make([]reflect.Value, 0),
+		namedValues:      make(map[string]providedValue),
+		unnamedValues:    make([]providedValue, 0),
 		namedFunctions:   make(map[string]InjectFunc),
+		namedFuncOrder:   make([]string, 0),
 		unnamedFunctions: make([]InjectFunc, 0),
+		modules:          make(map[string]*Module),
 		checker:          newInjectChecker(),
 		detector:         newCyclicDetector(),
 	}
@@ -51,28 +151,81 @@ func (c *Container) isStructPtrOrInterface(obj reflect.Value) bool {
 	return false
 }
 
+// lockRegistering lock c to register bindings, it panics if c is populating or frozen.
+func (c *Container) lockRegistering() {
+	c.mu.Lock()
+	if err := c.mutableError(); err != nil {
+		c.mu.Unlock()
+		panic(fmt.Errorf("provide error: %w", err))
+	}
+}
+
+// rlockPopulated read lock c to read populated state, it panics if c is populating.
+func (c *Container) rlockPopulated() {
+	c.mu.RLock()
+	if c.state == StatePopulating {
+		c.mu.RUnlock()
+		panic(fmt.Errorf("read container error: %v", errPopulating))
+	}
+}
+
+// bindingType return the type typ refers to, used by conditions and decorators.
+// Pointer to interface like (*Cache)(nil) refers to Cache, reflect.Type refers to itself
+// and other values refer to their own type like (*Config)(nil) refers to *Config.
+func bindingType(typ interface{}) reflect.Type {
+	if t, ok := typ.(reflect.Type); ok {
+		return t
+	}
+	t := reflect.TypeOf(typ)
+	if t == nil {
+		panic(fmt.Errorf("binding type should not be nil"))
+	}
+	if t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Interface {
+		return t.Elem()
+	}
+	return t
+}
+
 // Provide panics if objs are not pointer to struct or interface.
 func (c *Container) Provide(objs ...interface{}) {
+	c.provideValues(source{site: callerSite(1)}, "", objs...)
+}
+
+// ProvideWithLabel works like Provide and associate label with objs.
+// Like functions, objects with label are only injected if label is selected in Populate.
+func (c *Container) ProvideWithLabel(label string, objs ...interface{}) {
+	c.provideValues(source{site: callerSite(1)}, label, objs...)
+}
+
+func (c *Container) provideValues(src source, label string, objs ...interface{}) {
+	c.lockRegistering()
+	defer c.mu.Unlock()
+
 	for i := range objs {
 		v := reflect.ValueOf(objs[i])
 		if !c.isStructPtrOrInterface(v) {
 			panic(fmt.Errorf("check obj: %v error: %v", objs[i], errValueNotPtrOrInterface))
 		}
-		// fulfill already exists object
-		c.checker.popFulfilledUnnamedValues(v)
-		// extract injected struct fields
-		c.checker.pushInjectedFields(v)
-
-		// add cyclic detector
-		c.detector.AddDetectObject(v)
-
-		c.unnamedValues = append(c.unnamedValues, v)
+		c.unnamedValues = append(c.unnamedValues, providedValue{value: v, label: label, site: src.site, module: src.module, private: src.private})
 	}
 }
 
-// ProvideByName panics if name is duplicate.
+// ProvideByName panics if name is duplicate or reserved name "private".
 // Param name should match other object inject tag like `inject:"Name"`.
 func (c *Container) ProvideByName(name string, obj interface{}) {
+	c.provideNamedValue(source{site: callerSite(1)}, name, "", obj)
+}
+
+// ProvideByNameWithLabel works like ProvideByName and associate label with obj.
+func (c *Container) ProvideByNameWithLabel(name, label string, obj interface{}) {
+	c.provideNamedValue(source{site: callerSite(1)}, name, label, obj)
+}
+
+func (c *Container) provideNamedValue(src source, name, label string, obj interface{}) {
+	checkReservedName(name)
+	c.lockRegistering()
+	defer c.mu.Unlock()
+
 	v := reflect.ValueOf(obj)
 	if !c.isStructPtrOrInterface(v) {
 		panic(fmt.Errorf("check obj: %v error: %v", obj, errValueNotPtrOrInterface))
@@ -80,16 +233,7 @@ func (c *Container) ProvideByName(name string, obj interface{}) {
 	if _, ok := c.namedValues[name]; ok {
 		panic(fmt.Errorf("duplicate object name: %s", name))
 	}
-
-	// fulfill already exists object
-	c.checker.popFulfilledNamedValues(name, v)
-	// extract injected struct fields
-	c.checker.pushInjectedFields(v)
-
-	// add cyclic detector
-	c.detector.AddDetectObject(v)
-
-	c.namedValues[name] = v
+	c.namedValues[name] = providedValue{value: v, label: label, site: src.site, module: src.module, private: src.private}
 }
 
 // ProvideFunc support function types:
@@ -99,111 +243,463 @@ func (c *Container) ProvideByName(name string, obj interface{}) {
 // Param label is associated with fn and can be selected.
 // Only selected function will call.
 // If label is empty, by default it is selected.
+// Selected function with conditions will call only if all conditions match.
 func (c *Container) ProvideFunc(funcs ...InjectFunc) {
+	c.provideFuncs(source{site: callerSite(1)}, funcs...)
+}
+
+func (c *Container) provideFuncs(src source, funcs ...InjectFunc) {
+	for i := range funcs {
+		funcs[i].validate()
+	}
+
+	c.lockRegistering()
+	defer c.mu.Unlock()
 	for i := range funcs {
 		ifn := funcs[i]
-		ifn.validate()
+		ifn.site = src.site
+		ifn.module = src.module
+		ifn.private = src.private
+		if err := c.checkDecorated("", ifn); err != nil {
+			panic(err)
+		}
+		ifn.order = c.nextFuncOrder()
 
 		c.unnamedFunctions = append(c.unnamedFunctions, ifn)
 	}
 }
 
-// ProvideFuncByName use `name` as object name, panic if name is duplicate.
+// ProvideFuncByName use `name` as object name, panic if name is duplicate or reserved name "private".
 func (c *Container) ProvideFuncByName(name string, ifn InjectFunc) {
+	c.provideNamedFunc(source{site: callerSite(1)}, name, ifn)
+}
+
+func (c *Container) provideNamedFunc(src source, name string, ifn InjectFunc) {
+	checkReservedName(name)
 	ifn.validate()
+	ifn.site = src.site
+	ifn.module = src.module
+	ifn.private = src.private
 
+	c.lockRegistering()
+	defer c.mu.Unlock()
 	if _, ok := c.namedFunctions[name]; ok {
 		panic(fmt.Errorf("duplicate function name: %s", name))
 	}
 	if _, ok := c.namedValues[name]; ok {
 		panic(fmt.Errorf("duplicate object name: %s", name))
 	}
+	if err := c.checkDecorated(name, ifn); err != nil {
+		panic(err)
+	}
+	ifn.order = c.nextFuncOrder()
 	c.namedFunctions[name] = ifn
+	c.namedFuncOrder = append(c.namedFuncOrder, name)
 }
 
-func (c *Container) newObjectsByFunctions(labelSelector FuncLabelSelector) {
+// nextFuncOrder return order of a function being provided, c should be locked.
+func (c *Container) nextFuncOrder() int {
+	c.funcOrder++
+	return c.funcOrder
+}
+
+// recordSelected record v and where it comes from.
+func (c *Container) recordSelected(v reflect.Value, b binding, inner bool, wraps int) int {
+	if wraps >= 0 {
+		b.kind = NodeDecorator
+	}
+	c.selectedObjects = append(c.selectedObjects, selectedObject{binding: b, value: v, inner: inner, wraps: wraps})
+	return len(c.selectedObjects) - 1
+}
+
+func (c *Container) selectUnnamedValue(v reflect.Value, b binding) {
+	v, wraps := c.decorate(v, b)
+	c.recordSelected(v, b, false, wraps)
+	c.selectRefValue(v, b)
+
+	// fulfill already exists object
+	c.checker.popFulfilledUnnamedValues(v)
+	// extract injected struct fields
+	c.checker.pushInjectedFields(v)
+
+	// add cyclic detector
+	c.detector.AddDetectObject(v)
+
+	c.selectedUnnamedValues = append(c.selectedUnnamedValues, v)
+}
+
+func (c *Container) selectNamedValue(name string, v reflect.Value, b binding) {
+	v, wraps := c.decorate(v, b)
+	c.recordSelected(v, b, false, wraps)
+	c.selectRefValue(v, b)
+
+	// fulfill already exists object
+	c.checker.popFulfilledNamedValues(name, v)
+	// extract injected struct fields
+	c.checker.pushInjectedFields(v)
+
+	// add cyclic detector
+	c.detector.AddDetectObject(v)
+
+	c.selectedNamedValues[name] = v
+}
+
+// selectObjects reset checker and detector, then add provided objects selected by labelSelector.
+// Objects populated by previous Populate fulfill fields of new objects but are not populated again.
+func (c *Container) selectObjects(labelSelector FuncLabelSelector) {
+	c.checker = newInjectChecker()
+	c.detector = newCyclicDetector()
+	c.selectedNamedValues = make(map[string]reflect.Value)
+	c.selectedUnnamedValues = make([]reflect.Value, 0)
+	c.selectedInnerValues = make([]reflect.Value, 0)
+	c.selectedTransients = make([]*transient, 0)
+	c.selectedObjects = c.selectedObjects[:c.populatedObjects]
+
+	fulfill := func(name string, v reflect.Value) {
+		if name != "" {
+			c.checker.popFulfilledNamedValues(name, v)
+		} else {
+			c.checker.popFulfilledUnnamedValues(v)
+		}
+	}
+	for _, o := range c.selectedObjects {
+		if !o.inner {
+			fulfill(o.name, o.value)
+		}
+	}
+	c.eachParentBinding(fulfill)
+
+	for i := range c.unnamedValues {
+		pv := c.unnamedValues[i]
+		if pv.populated || !isLabelSelected(labelSelector, pv.label) {
+			continue
+		}
+		c.selectUnnamedValue(pv.value, binding{label: pv.label, site: pv.site, module: pv.module, private: pv.private, kind: NodeObject})
+	}
+	for _, name := range c.sortedValueNames() {
+		pv := c.namedValues[name]
+		if pv.populated || !isLabelSelected(labelSelector, pv.label) {
+			continue
+		}
+		c.selectNamedValue(name, pv.value, binding{name: name, label: pv.label, site: pv.site, module: pv.module, private: pv.private, kind: NodeObject})
+	}
+}
+
+// evaluateConditions decide which selected functions will be called.
+// Selected objects and functions without conditions are added to bindings first,
+// then conditional functions are evaluated in provided order, unnamed functions before named functions.
+// A matched function is added to bindings and visible to conditions evaluated after it.
+func (c *Container) evaluateConditions(labelSelector FuncLabelSelector) {
+	c.unmatchedNamedFunctions = make(map[string]bool)
+	c.unmatchedUnnamedFunctions = make(map[int]bool)
+	c.conditionResults = make([]ConditionResult, 0)
+
+	bindings := newBindingSet()
+	add := func(name string, v reflect.Value) {
+		if name != "" {
+			bindings.addName(name)
+		} else {
+			bindings.addType(v.Type())
+		}
+	}
+	for _, o := range c.selectedObjects {
+		if !o.inner {
+			add(o.name, o.value)
+		}
+	}
+	c.eachParentBinding(add)
+	// objects of request scoped functions are only visible to children
 	for i := range c.unnamedFunctions {
-		label := c.unnamedFunctions[i].Label
-		if labelSelector != nil && label != "" && !labelSelector.IsLabelAllowed(label) {
+		ifn := c.unnamedFunctions[i]
+		if !ifn.populated && ifn.Scope != ScopeRequest && len(ifn.Conditions) == 0 && isLabelSelected(labelSelector, ifn.Label) {
+			bindings.addType(ifn.returnType())
+		}
+	}
+	for _, name := range c.namedFuncOrder {
+		ifn := c.namedFunctions[name]
+		if !ifn.populated && ifn.Scope != ScopeRequest && len(ifn.Conditions) == 0 && isLabelSelected(labelSelector, ifn.Label) {
+			bindings.addName(name)
+		}
+	}
+
+	for _, f := range c.conditionalFunctions(labelSelector) {
+		matched, results := f.ifn.matchConditions(f.name, bindings)
+		c.conditionResults = append(c.conditionResults, results...)
+		if !matched {
+			if f.name == "" {
+				c.unmatchedUnnamedFunctions[f.index] = true
+			} else {
+				c.unmatchedNamedFunctions[f.name] = true
+			}
 			continue
 		}
-		v, err := c.unnamedFunctions[i].create()
-		if err != nil {
-			panic(fmt.Errorf("unamed function error: %v", err))
+		if f.ifn.Scope == ScopeRequest {
+			continue
 		}
+		if f.name == "" {
+			bindings.addType(f.ifn.returnType())
+		} else {
+			bindings.addName(f.name)
+		}
+	}
+}
 
-		c.unnamedFunctions[i].setReceiver(v)
+// conditionalFunction is a function with conditions, index is its index in unnamedFunctions if it is unnamed.
+type conditionalFunction struct {
+	name  string
+	index int
+	ifn   InjectFunc
+}
+
+// conditionalFunctions return selected functions with conditions not populated, in provided order.
+func (c *Container) conditionalFunctions(labelSelector FuncLabelSelector) []conditionalFunction {
+	funcs := make([]conditionalFunction, 0)
+	selected := func(ifn InjectFunc) bool {
+		return !ifn.populated && len(ifn.Conditions) > 0 && isLabelSelected(labelSelector, ifn.Label)
+	}
+	for i := range c.unnamedFunctions {
+		if selected(c.unnamedFunctions[i]) {
+			funcs = append(funcs, conditionalFunction{index: i, ifn: c.unnamedFunctions[i]})
+		}
+	}
+	for _, name := range c.namedFuncOrder {
+		if selected(c.namedFunctions[name]) {
+			funcs = append(funcs, conditionalFunction{name: name, ifn: c.namedFunctions[name]})
+		}
+	}
+	sort.SliceStable(funcs, func(i, j int) bool {
+		return funcs[i].ifn.order < funcs[j].ifn.order
+	})
+	return funcs
+}
+
+// ConditionReport return results of function conditions evaluated in last Populate, in evaluated order.
+func (c *Container) ConditionReport() []ConditionResult {
+	c.rlockPopulated()
+	defer c.mu.RUnlock()
+	return c.conditionResults
+}
+
+// skipReason return why function is not called, empty if it is called.
+func skipReason(labelSelector FuncLabelSelector, ifn InjectFunc, unmatched bool) string {
+	if !isLabelSelected(labelSelector, ifn.Label) {
+		return "label not selected"
+	}
+	if unmatched {
+		return "conditions not match"
+	}
+	return ""
+}
 
-		// fulfill already exists object
-		c.checker.popFulfilledUnnamedValues(v)
-		// extract injected struct fields
-		c.checker.pushInjectedFields(v)
+func (c *Container) recordFunction(name string, ifn InjectFunc, reason string) {
+	c.functionResults = append(c.functionResults, functionResult{
+		name:     name,
+		label:    ifn.Label,
+		tp:       ifn.returnType(),
+		selected: reason == "",
+		reason:   reason,
+	})
+}
 
-		// add cyclic detector
-		c.detector.AddDetectObject(v)
+func (c *Container) newObjectsByFunctions(labelSelector FuncLabelSelector) {
+	for i := range c.unnamedFunctions {
+		if c.unnamedFunctions[i].populated {
+			continue
+		}
+		reason := skipReason(labelSelector, c.unnamedFunctions[i], c.unmatchedUnnamedFunctions[i])
+		c.recordFunction("", c.unnamedFunctions[i], reason)
+		if reason != "" {
+			continue
+		}
+		switch c.unnamedFunctions[i].Scope {
+		case ScopeTransient:
+			c.selectTransient("", c.unnamedFunctions[i])
+			continue
+		case ScopeRequest:
+			c.requestFunctions = append(c.requestFunctions, &transient{ifn: c.unnamedFunctions[i]})
+			continue
+		}
+		v, err := c.unnamedFunctions[i].create()
+		if err != nil {
+			panic(fmt.Errorf("unamed function%s error: %v", c.unnamedFunctions[i].moduleText(), err))
+		}
 
-		c.unnamedValues = append(c.unnamedValues, v)
+		ifn := c.unnamedFunctions[i]
+		ifn.setReceiver(v)
+		c.selectUnnamedValue(v, binding{label: ifn.Label, site: ifn.site, module: ifn.module, private: ifn.private, kind: NodeFunction})
 	}
-	for name, fn := range c.namedFunctions {
-		label := fn.Label
-		if labelSelector != nil && label != "" && !labelSelector.IsLabelAllowed(label) {
+	for _, name := range c.namedFuncOrder {
+		fn := c.namedFunctions[name]
+		if fn.populated {
+			continue
+		}
+		reason := skipReason(labelSelector, fn, c.unmatchedNamedFunctions[name])
+		c.recordFunction(name, fn, reason)
+		if reason != "" {
+			continue
+		}
+		switch fn.Scope {
+		case ScopeTransient:
+			c.selectTransient(name, fn)
+			continue
+		case ScopeRequest:
+			c.requestFunctions = append(c.requestFunctions, &transient{name: name, ifn: fn})
 			continue
 		}
 
 		v, err := fn.create()
 		if err != nil {
-			panic(fmt.Errorf("function %s error: %v", name, err))
+			panic(fmt.Errorf("function %s%s error: %v", name, fn.moduleText(), err))
 		}
 
 		fn.setReceiver(v)
+		c.selectNamedValue(name, v, binding{name: name, label: fn.Label, site: fn.site, module: fn.module, private: fn.private, kind: NodeFunction})
+	}
+}
 
-		// fulfill already exists object
-		c.checker.popFulfilledNamedValues(name, v)
-		// extract injected struct fields
-		c.checker.pushInjectedFields(v)
-
-		// add cyclic detector
-		c.detector.AddDetectObject(v)
-
-		c.namedValues[name] = v
+// markPopulated mark provided objects and functions selected by labelSelector as populated,
+// they are skipped by next Populate. Functions whose conditions not match are not selected.
+func (c *Container) markPopulated(labelSelector FuncLabelSelector) {
+	for i := range c.unnamedValues {
+		if isLabelSelected(labelSelector, c.unnamedValues[i].label) {
+			c.unnamedValues[i].populated = true
+		}
+	}
+	for name, pv := range c.namedValues {
+		if isLabelSelected(labelSelector, pv.label) {
+			pv.populated = true
+			c.namedValues[name] = pv
+		}
+	}
+	for i := range c.unnamedFunctions {
+		if skipReason(labelSelector, c.unnamedFunctions[i], c.unmatchedUnnamedFunctions[i]) == "" {
+			c.unnamedFunctions[i].populated = true
+		}
+	}
+	for name, ifn := range c.namedFunctions {
+		if skipReason(labelSelector, ifn, c.unmatchedNamedFunctions[name]) == "" {
+			ifn.populated = true
+			c.namedFunctions[name] = ifn
+		}
 	}
 }
 
 func (c *Container) provideObjects() {
-	for i := range c.unnamedValues {
-		c.graph.ProvideObj(c.unnamedValues[i])
+	for i := range c.selectedInnerValues {
+		c.graph.ProvideInnerObj(c.selectedInnerValues[i])
+	}
+	for i := range c.selectedUnnamedValues {
+		c.graph.ProvideObj(c.selectedUnnamedValues[i])
 	}
-	for name, v := range c.namedValues {
+	for name, v := range c.selectedNamedValues {
 		c.graph.ProvideNamedObj(name, v)
 	}
+	for i := range c.selectedTransients {
+		c.graph.ProvideTransient(c.selectedTransients[i])
+	}
 }
 
 // Populate call all provided functions then inject all provided and returned by function objects.
-// It panics if any error occurs.
-// Param labelSelector choice function with it's label. If nil passed, all function will selected.
+// It panics if any error occurs, with *UnfulfilledError if some inject fields have no matching object
+// and *CyclicError if dependency cyclic exists.
+// Param labelSelector choice objects and functions with their label. If nil passed, all of them will selected.
+// Only selected objects are checked for unfulfilled fields and dependency cyclic.
 // If Initializable is implemented, Init method will be called after object populated.
+//
+// Populate can be called again to populate objects and functions provided after or not selected by
+// previous Populate, against objects already populated. Objects and functions selected by previous
+// Populate are untouched, their functions are not called again and Init is only called on new objects.
+// If Populate panics before injecting, new objects and functions are kept for next Populate.
+//
+// Providing to c while it is populating panics, including from functions and Init methods called by Populate.
+// Container is frozen after Populate succeeds, call Unfreeze to provide objects for next Populate.
 func (c *Container) Populate(labelSelector FuncLabelSelector) {
+	c.mu.Lock()
+	prevState := c.state
+	switch prevState {
+	case StatePopulating:
+		c.mu.Unlock()
+		panic(fmt.Errorf("populate error: %v", errPopulating))
+	case StateClosing, StateClosed:
+		c.mu.Unlock()
+		panic(fmt.Errorf("populate error: %v", errClosed))
+	}
+	c.state = StatePopulating
+	c.mu.Unlock()
+	functionResults, requestFunctions := len(c.functionResults), len(c.requestFunctions)
+	injecting, succeeded := false, false
+	var graph *objectGraph // graph before objects are provided to it
+	defer func() {
+		c.mu.Lock()
+		if !injecting {
+			c.selectedObjects = c.selectedObjects[:c.populatedObjects]
+			c.functionResults = c.functionResults[:functionResults]
+			c.requestFunctions = c.requestFunctions[:requestFunctions]
+			if graph != nil {
+				c.graph.restore(graph)
+			}
+		}
+		if injecting {
+			c.populatedObjects = len(c.selectedObjects)
+		}
+		c.state = prevState
+		if succeeded {
+			c.state = StatePopulated
+			c.frozen = true
+			c.populated = true
+		}
+		c.mu.Unlock()
+	}()
+
+	c.selectObjects(labelSelector)
+	c.evaluateConditions(labelSelector)
 	c.newObjectsByFunctions(labelSelector)
 
 	c.checker.popRemainedValues()
+	c.selectFallbackObjects()
 	if !c.checker.isAllFulfilled() {
 		unnamed := c.checker.getUnfulfilledUnnamedValues()
 		named := c.checker.getUnfulfilledNamedValues()
-		panic(fmt.Errorf("named unfulfilled objects: %v, unnamed unfulfilled objects: %s",
-			(namedValueMap)(named).prettify(), (fieldValueMap)(unnamed).prettify()))
+		panic(&UnfulfilledError{Named: named, Unnamed: unnamed})
 	}
 
 	existsCyclic, cyclicPath := c.detector.DetectCyclic()
 	if existsCyclic {
-		panic(fmt.Errorf("dependency cyclic detected, cyclic path %s", cyclicPath.prettify()))
+		panic(&CyclicError{Path: cyclicPath})
 	}
 
+	graph = c.graph.snapshot()
 	c.provideObjects()
+	if violations := c.visibilityViolations(); len(violations) > 0 {
+		panic(&VisibilityError{Violations: violations})
+	}
+
+	injecting = true
+	c.markPopulated(labelSelector)
 	c.graph.Populate()
+	c.recordTransientObjects()
+	succeeded = true
 }
 
 // Close will call Close method if Closable is implemented.
+// Objects swapped out by Swap and waiting for grace period are closed first, Close returns after they are closed.
 // It panics if any error occurs.
+// Container is frozen and closed after Close.
 func (c *Container) Close() {
+	c.mu.Lock()
+	if c.state == StatePopulating {
+		c.mu.Unlock()
+		panic(fmt.Errorf("close error: %v", errPopulating))
+	}
+	c.state = StateClosing
+	c.frozen = true
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		c.state = StateClosed
+		c.mu.Unlock()
+	}()
+
+	c.flushGraceCloses()
 	c.graph.Close()
 }
@@ -7,9 +7,17 @@ import (
 
 // InjectFunc contains a function to new object and label of the function.
 type InjectFunc struct {
-	Fn       interface{} // func() T / func() (T, error)
-	Label    string      // default selected
-	Receiver interface{} // *T, receive object from Fn
+	Fn         interface{} // func() T / func() (T, error)
+	Label      string      // default selected
+	Receiver   interface{} // *T, receive object from Fn
+	Conditions []Condition // Fn is called only if all conditions match
+	Scope      Scope       // ScopeSingleton by default
+
+	site      string // file:line where function is provided
+	module    string // name of module installs the function
+	private   bool   // only injected into objects of the same module
+	populated bool   // selected by a previous Populate
+	order     int    // provided order in container, conditions are evaluated in this order
 }
 
 func (ifn InjectFunc) validate() {
@@ -29,6 +37,15 @@ func (ifn InjectFunc) validate() {
 			panic(fmt.Errorf("func %v second return value should be error", ifn))
 		}
 	}
+	switch ifn.Scope {
+	case ScopeSingleton:
+	case ScopeTransient, ScopeRequest:
+		if ifn.Receiver != nil {
+			panic(fmt.Errorf("%v func %v should not set receiver", ifn.Scope, ifn))
+		}
+	default:
+		panic(fmt.Errorf("func %v unknown scope %v", ifn, ifn.Scope))
+	}
 }
 
 func (ifn InjectFunc) create() (reflect.Value, error) {
@@ -47,8 +64,52 @@ func (ifn InjectFunc) create() (reflect.Value, error) {
 	panic(fmt.Errorf("call unsupport function"))
 }
 
+// concreteValue return the value stored in v if v is a non-nil interface.
+func concreteValue(v reflect.Value) reflect.Value {
+	if v.Kind() == reflect.Interface && !v.IsNil() {
+		return v.Elem()
+	}
+	return v
+}
+
 func (ifn InjectFunc) setReceiver(obj reflect.Value) {
 	if ifn.Receiver != nil {
 		reflect.ValueOf(ifn.Receiver).Elem().Set(obj)
 	}
 }
+
+func (ifn InjectFunc) clone() InjectFunc {
+	n := ifn
+	n.Conditions = append([]Condition(nil), ifn.Conditions...)
+	return n
+}
+
+// moduleText return " of module <name>" if function is installed by module, used in error messages.
+func (ifn InjectFunc) moduleText() string {
+	if ifn.module == "" {
+		return ""
+	}
+	return fmt.Sprintf(" of module %s", ifn.module)
+}
+
+// returnType return type T of func() T / func() (T, error).
+func (ifn InjectFunc) returnType() reflect.Type {
+	return reflect.Indirect(reflect.ValueOf(ifn.Fn)).Type().Out(0)
+}
+
+// matchConditions evaluate all conditions in order and return true if all of them match.
+func (ifn InjectFunc) matchConditions(name string, b Bindings) (bool, []ConditionResult) {
+	matched := true
+	results := make([]ConditionResult, 0, len(ifn.Conditions))
+	for i := range ifn.Conditions {
+		ok := ifn.Conditions[i].Match(b)
+		results = append(results, ConditionResult{
+			Name:      name,
+			Type:      ifn.returnType(),
+			Condition: ifn.Conditions[i].String(),
+			Matched:   ok,
+		})
+		matched = matched && ok
+	}
+	return matched, results
+}
@@ -351,3 +351,158 @@ func TestInjectFunctions_DependencyCyclic(t *testing.T) {
 		container.Populate(nil)
 	}, "should panic because dependency cyclic exists")
 }
+
+func TestInjectObjects_LabelSelect(t *testing.T) {
+	type B struct {
+		Name string
+	}
+
+	type A struct {
+		B *B `inject:""`
+	}
+
+	type NamedA struct {
+		B *B `inject:"NameB"`
+	}
+
+	a := &A{}
+	na := &NamedA{}
+	devB := &B{"dev b"}
+	prodB := &B{"prod b"}
+
+	c := NewContainer()
+	c.Provide(a, na)
+	c.ProvideWithLabel("dev", devB)
+	c.ProvideWithLabel("prod", prodB)
+	c.ProvideByNameWithLabel("NameB", "prod", prodB)
+	c.Populate(labelSelector{labels: []string{"prod"}})
+
+	assert.Equal(t, prodB, a.B)
+	assert.Equal(t, prodB, na.B)
+
+	/// test unselected object is not checked
+	type Unfulfilled struct {
+		B *B `inject:"missing"`
+	}
+	a = &A{}
+	c = NewContainer()
+	c.Provide(a, devB)
+	c.ProvideWithLabel("test", &Unfulfilled{})
+	c.Populate(labelSelector{labels: []string{"dev"}})
+	assert.Equal(t, devB, a.B)
+
+	/// test unselected object can not fulfill fields
+	c = NewContainer()
+	c.Provide(&A{})
+	c.ProvideWithLabel("dev", devB)
+	assert.Panics(t, func() {
+		c.Populate(labelSelector{labels: []string{"prod"}})
+	}, "should panic because labeled object not selected")
+}
+
+type incrementalDB struct {
+	inits int
+}
+
+func (d *incrementalDB) Init() error {
+	d.inits++
+	return nil
+}
+
+type incrementalRepo struct {
+	DB    *incrementalDB `inject:""`
+	inits int
+}
+
+func (r *incrementalRepo) Init() error {
+	r.inits++
+	return nil
+}
+
+type incrementalHandler struct {
+	DB   *incrementalDB   `inject:""`
+	Repo *incrementalRepo `inject:""`
+	Name fmt.Stringer     `inject:"name"`
+}
+
+func TestPopulate_Incremental(t *testing.T) {
+	c := NewContainer()
+	db := &incrementalDB{}
+	calls := 0
+	c.Provide(db)
+	c.ProvideFunc(InjectFunc{
+		Fn: func() *incrementalRepo {
+			calls++
+			return &incrementalRepo{}
+		},
+	})
+	c.Populate(nil)
+	assert.Equal(t, 1, db.inits)
+	repo, ok := c.LookupType((*incrementalRepo)(nil))
+	assert.True(t, ok)
+	assert.Equal(t, db, repo.(*incrementalRepo).DB)
+
+	/// test new objects are populated against existing objects
+	c.Unfreeze()
+	h := &incrementalHandler{}
+	c.Provide(h)
+	c.ProvideByName("name", &Person{Name: "p"})
+	c.Populate(nil)
+	assert.Equal(t, 1, calls, "function should not be called again")
+	assert.Equal(t, 1, db.inits, "existing object should not be initialized again")
+	assert.Equal(t, 1, repo.(*incrementalRepo).inits)
+	assert.Equal(t, db, h.DB)
+	assert.Equal(t, repo, h.Repo)
+	assert.Equal(t, "name:p", h.Name.String())
+	assert.Len(t, c.Graph().Nodes, 4)
+
+	c.Unfreeze()
+	assert.Panics(t, func() {
+		c.ProvideByName("name", &Person{})
+	}, "should panic because name is duplicate")
+
+	/// test failed populate keeps new objects for next populate
+	type named struct {
+		Name fmt.Stringer   `inject:"name2"`
+		DB   *incrementalDB `inject:""`
+	}
+	n := &named{}
+	c.Provide(n)
+	assert.Panics(t, func() {
+		c.Populate(nil)
+	}, "should panic because name2 is not provided")
+	assert.Len(t, c.Graph().Nodes, 4)
+	assert.False(t, c.Frozen(), "should not freeze if populate failed")
+
+	c.ProvideByNameWithLabel("name2", "prod", &Person{Name: "p2"})
+	c.ProvideByNameWithLabel("name3", "dev", &Person{Name: "p3"})
+	devCalls := 0
+	c.ProvideFunc(InjectFunc{
+		Label: "dev",
+		Fn: func() *Person {
+			devCalls++
+			return &Person{Name: "dev"}
+		},
+	})
+	c.Populate(labelSelector{labels: []string{"prod"}})
+	assert.Equal(t, db, n.DB)
+	assert.Equal(t, "name:p2", n.Name.String())
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 0, devCalls)
+	assert.Equal(t, 1, db.inits)
+	assert.Len(t, c.Graph().Nodes, 6)
+
+	/// test bindings not selected by previous populate are selected by next populate
+	c.Unfreeze()
+	c.Populate(labelSelector{labels: []string{"dev"}})
+	assert.Equal(t, 1, devCalls)
+	p3, ok := c.Lookup("name3")
+	assert.True(t, ok)
+	assert.Equal(t, "p3", p3.(*Person).Name)
+	assert.Len(t, c.Graph().Nodes, 8)
+
+	c.Unfreeze()
+	c.Populate(nil)
+	assert.Equal(t, 1, devCalls, "function should not be called again")
+	assert.Len(t, c.Graph().Nodes, 8)
+}
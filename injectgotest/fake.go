@@ -0,0 +1,76 @@
+package injectgotest
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/RivenZoo/injectgo"
+)
+
+// fake creates fake implementation of interface tp.
+type fake struct {
+	tp      reflect.Type
+	newFake func() interface{}
+}
+
+var (
+	fakesLock sync.Mutex
+	fakes     []fake
+)
+
+// RegisterFake registers function newFake creating fake implementation of interface iface points to,
+// eg. RegisterFake((*Repo)(nil), func() interface{} { return &FakeRepo{} }).
+// Fakes generated by `injectgo fake` are registered in init of generated file.
+func RegisterFake(iface interface{}, newFake func() interface{}) {
+	tp := reflect.TypeOf(iface)
+	if tp == nil || tp.Kind() != reflect.Ptr || tp.Elem().Kind() != reflect.Interface {
+		panic(fmt.Sprintf("fake of %v should be registered with pointer to interface", tp))
+	}
+	tp = tp.Elem()
+
+	fakesLock.Lock()
+	defer fakesLock.Unlock()
+	for i := range fakes {
+		if fakes[i].tp == tp {
+			fakes[i].newFake = newFake
+			return
+		}
+	}
+	fakes = append(fakes, fake{tp: tp, newFake: newFake})
+}
+
+// registeredFakes return a copy of fakes in registering order.
+func registeredFakes() []fake {
+	fakesLock.Lock()
+	defer fakesLock.Unlock()
+	return append([]fake(nil), fakes...)
+}
+
+// newFakeOf return a new fake of the first registered fake whose interface is tp.
+func newFakeOf(fakes []fake, tp reflect.Type) (interface{}, bool) {
+	for _, f := range fakes {
+		if f.tp != tp {
+			continue
+		}
+		obj := f.newFake()
+		if obj == nil || !reflect.TypeOf(obj).Implements(f.tp) {
+			panic(fmt.Sprintf("fake %T does not implement %v", obj, f.tp))
+		}
+		return obj, true
+	}
+	return nil, false
+}
+
+// Option configures container created by New.
+type Option func(c *injectgo.Container)
+
+// WithFakes provides registered fakes for interface fields left unfulfilled by the container when populate,
+// named fields included, a new fake is created for each unfulfilled name and unnamed interface.
+func WithFakes() Option {
+	return func(c *injectgo.Container) {
+		c.Fallback(func(name string, tp reflect.Type) (interface{}, bool) {
+			return newFakeOf(registeredFakes(), tp)
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package injectgotest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type Store interface {
+	Get(key string) string
+}
+
+type fakeStore struct {
+	calls []string
+}
+
+func (s *fakeStore) Get(key string) string {
+	s.calls = append(s.calls, key)
+	return "fake"
+}
+
+type realStore struct{}
+
+func (realStore) Get(key string) string {
+	return "real"
+}
+
+type storeUser struct {
+	Store Store `inject:""`
+}
+
+type namedStoreUser struct {
+	Cache Store `inject:"cache"`
+	DB    Store `inject:"db"`
+}
+
+func TestWithFakes(t *testing.T) {
+	RegisterFake((*Store)(nil), func() interface{} { return &fakeStore{} })
+
+	c := New(t, WithFakes())
+	user := &storeUser{}
+	c.Provide(user)
+	MustPopulate(t, c, nil)
+	fake, ok := user.Store.(*fakeStore)
+	assert.True(t, ok)
+	assert.Equal(t, "fake", user.Store.Get("a"))
+	assert.Equal(t, []string{"a"}, fake.calls)
+
+	c = New(t, WithFakes())
+	user = &storeUser{}
+	c.Provide(user, &realStore{})
+	MustPopulate(t, c, nil)
+	assert.Equal(t, "real", user.Store.Get("a"))
+
+	c = New(t, WithFakes())
+	named := &namedStoreUser{}
+	c.Provide(named)
+	c.ProvideByName("db", &realStore{})
+	MustPopulate(t, c, nil)
+	_, ok = named.Cache.(*fakeStore)
+	assert.True(t, ok)
+	assert.Equal(t, "real", named.DB.Get("a"))
+
+	assert.Panics(t, func() {
+		RegisterFake(fakeStore{}, func() interface{} { return &fakeStore{} })
+	})
+}
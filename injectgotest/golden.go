@@ -0,0 +1,49 @@
+package injectgotest
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/RivenZoo/injectgo"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+var update = flag.Bool("injectgo.update", false, "update golden manifest files of AssertManifest")
+
+// AssertManifest compare manifest of populated c with golden file.
+// Run test with -injectgo.update to create or update the golden file.
+func AssertManifest(t testing.TB, c *injectgo.Container, golden string) bool {
+	t.Helper()
+
+	actual := c.Manifest()
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(golden), 0755); err != nil {
+			t.Fatalf("create golden dir: %v", err)
+		}
+		if err := ioutil.WriteFile(golden, []byte(actual), 0644); err != nil {
+			t.Fatalf("update golden file: %v", err)
+		}
+		return true
+	}
+
+	expected, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Errorf("read golden file: %v, run test with -injectgo.update to create it", err)
+		return false
+	}
+	if string(expected) == actual {
+		return true
+	}
+	diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(expected)),
+		B:        difflib.SplitLines(actual),
+		FromFile: golden,
+		ToFile:   "actual",
+		Context:  2,
+	})
+	t.Errorf("wiring manifest changed, run test with -injectgo.update if it is expected:\n%s", diff)
+	return false
+}
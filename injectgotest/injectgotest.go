@@ -0,0 +1,166 @@
+// Package injectgotest provides helpers to build and check injectgo containers in tests.
+package injectgotest
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/RivenZoo/injectgo"
+)
+
+const injectTag = "inject"
+
+// New return a new container configured by opts, which is closed when t and all its subtests complete.
+func New(t testing.TB, opts ...Option) *injectgo.Container {
+	t.Helper()
+
+	c := injectgo.NewContainer()
+	for _, opt := range opts {
+		opt(c)
+	}
+	t.Cleanup(func() {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Errorf("close container: %v", r)
+			}
+		}()
+		c.Close()
+	})
+	return c
+}
+
+// MustPopulate populate c with labelSelector, any panic is reported by t.Fatal.
+func MustPopulate(t testing.TB, c *injectgo.Container, labelSelector injectgo.FuncLabelSelector) {
+	t.Helper()
+
+	failed := true
+	var r interface{}
+	func() {
+		defer func() {
+			r = recover()
+		}()
+		c.Populate(labelSelector)
+		failed = false
+	}()
+	if failed {
+		t.Fatalf("populate container failed:\n%s", Report(r))
+	}
+}
+
+// Report return a readable multi-line report of value panicked by Populate.
+func Report(r interface{}) string {
+	switch e := r.(type) {
+	case *injectgo.UnfulfilledError:
+		lines := make([]string, 0, len(e.Named)+len(e.Unnamed))
+		for name, v := range e.Named {
+			lines = append(lines, fmt.Sprintf("\t%v needs object named %q", v.Type(), name))
+		}
+		for tp, v := range e.Unnamed {
+			lines = append(lines, fmt.Sprintf("\t%v needs object of type %v", v.Type(), tp))
+		}
+		sort.Strings(lines)
+		return fmt.Sprintf("unfulfilled dependencies:\n%s", strings.Join(lines, "\n"))
+	case *injectgo.CyclicError:
+		path := make([]string, 0, len(e.Path))
+		for i := range e.Path {
+			path = append(path, fmt.Sprintf("\t%v", e.Path[i]))
+		}
+		return fmt.Sprintf("dependency cyclic:\n%s", strings.Join(path, " ->\n"))
+	default:
+		return fmt.Sprintf("%v", r)
+	}
+}
+
+// injectFields return inject tagged fields of struct obj points to.
+func injectFields(obj interface{}) (reflect.Value, []reflect.StructField, error) {
+	v := reflect.Indirect(reflect.ValueOf(obj))
+	if v.Kind() != reflect.Struct {
+		return v, nil, fmt.Errorf("object %v should be pointer to struct", obj)
+	}
+	fields := make([]reflect.StructField, 0)
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if _, ok := t.Field(i).Tag.Lookup(injectTag); ok {
+			fields = append(fields, t.Field(i))
+		}
+	}
+	return v, fields, nil
+}
+
+// AssertInjected checks all inject tagged fields of obj are not nil.
+func AssertInjected(t testing.TB, obj interface{}) bool {
+	t.Helper()
+
+	v, fields, err := injectFields(obj)
+	if err != nil {
+		t.Error(err)
+		return false
+	}
+	nilFields := make([]string, 0)
+	for i := range fields {
+		if v.FieldByIndex(fields[i].Index).IsNil() {
+			nilFields = append(nilFields, fields[i].Name)
+		}
+	}
+	if len(nilFields) > 0 {
+		t.Errorf("%v has not injected fields: %s", v.Type(), strings.Join(nilFields, ", "))
+		return false
+	}
+	return true
+}
+
+// AssertGraph checks inject tagged fields of obj are the expected instances.
+// Param expected is field name -> expected instance.
+func AssertGraph(t testing.TB, obj interface{}, expected map[string]interface{}) bool {
+	t.Helper()
+
+	v, fields, err := injectFields(obj)
+	if err != nil {
+		t.Error(err)
+		return false
+	}
+	tagged := make(map[string]bool, len(fields))
+	for i := range fields {
+		tagged[fields[i].Name] = true
+	}
+
+	names := make([]string, 0, len(expected))
+	for name := range expected {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ok := true
+	for _, name := range names {
+		if !tagged[name] {
+			t.Errorf("%v has no inject field %s", v.Type(), name)
+			ok = false
+			continue
+		}
+		actual := v.FieldByName(name).Interface()
+		if !isSameInstance(actual, expected[name]) {
+			t.Errorf("%v field %s is %v(%p), expected %v(%p)",
+				v.Type(), name, actual, actual, expected[name], expected[name])
+			ok = false
+		}
+	}
+	return ok
+}
+
+// isSameInstance compare pointers by address and other values by equality.
+func isSameInstance(actual, expected interface{}) bool {
+	av, ev := reflect.ValueOf(actual), reflect.ValueOf(expected)
+	if !av.IsValid() || !ev.IsValid() {
+		return av.IsValid() == ev.IsValid()
+	}
+	if av.Type() != ev.Type() {
+		return false
+	}
+	if av.Kind() == reflect.Ptr {
+		return av.Pointer() == ev.Pointer()
+	}
+	return av.Type().Comparable() && actual == expected
+}
@@ -0,0 +1,159 @@
+package injectgotest
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fatalMsg string
+
+// recordT records failures instead of failing the test.
+type recordT struct {
+	testing.TB
+	errors   []string
+	cleanups []func()
+}
+
+func (r *recordT) Helper() {}
+
+func (r *recordT) Error(args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprint(args...))
+}
+
+func (r *recordT) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recordT) Fatalf(format string, args ...interface{}) {
+	panic(fatalMsg(fmt.Sprintf(format, args...)))
+}
+
+func (r *recordT) Cleanup(fn func()) {
+	r.cleanups = append(r.cleanups, fn)
+}
+
+func (r *recordT) runCleanups() {
+	for i := len(r.cleanups) - 1; i >= 0; i-- {
+		r.cleanups[i]()
+	}
+}
+
+type Client struct {
+	closed bool
+}
+
+func (c *Client) Close() error {
+	c.closed = true
+	return nil
+}
+
+type Service struct {
+	Client   *Client      `inject:""`
+	Stringer fmt.Stringer `inject:"name"`
+	Name     string
+}
+
+type cyclicA struct {
+	B *cyclicB `inject:""`
+}
+
+type cyclicB struct {
+	A *cyclicA `inject:""`
+}
+
+type name struct {
+	value string
+}
+
+func (n *name) String() string {
+	return n.value
+}
+
+func TestNewMustPopulate(t *testing.T) {
+	rt := &recordT{TB: t}
+	c := New(rt)
+
+	s := &Service{}
+	cli := &Client{}
+	n := &name{"service"}
+	c.Provide(s, cli)
+	c.ProvideByName("name", n)
+	MustPopulate(rt, c, nil)
+
+	assert.True(t, AssertInjected(rt, s))
+	assert.True(t, AssertGraph(rt, s, map[string]interface{}{
+		"Client":   cli,
+		"Stringer": n,
+	}))
+	assert.Empty(t, rt.errors)
+
+	rt.runCleanups()
+	assert.True(t, cli.closed)
+}
+
+func TestMustPopulate_Fatal(t *testing.T) {
+	rt := &recordT{TB: t}
+	c := New(rt)
+	c.Provide(&Service{})
+
+	msg := recoverFatal(func() { MustPopulate(rt, c, nil) })
+	assert.Contains(t, msg, "unfulfilled dependencies")
+	assert.Contains(t, msg, `needs object named "name"`)
+	assert.Contains(t, msg, "needs object of type *injectgotest.Client")
+	t.Log(msg)
+
+	c = New(rt)
+	c.Provide(&cyclicA{}, &cyclicB{})
+	msg = recoverFatal(func() { MustPopulate(rt, c, nil) })
+	assert.Contains(t, msg, "dependency cyclic")
+	t.Log(msg)
+}
+
+func TestAssertFailed(t *testing.T) {
+	rt := &recordT{TB: t}
+	s := &Service{Client: &Client{}}
+
+	assert.False(t, AssertInjected(rt, s))
+	assert.False(t, AssertGraph(rt, s, map[string]interface{}{
+		"Client": &Client{},
+		"Name":   "",
+	}))
+	assert.False(t, AssertInjected(rt, Service{}))
+	assert.Len(t, rt.errors, 4)
+	t.Log(rt.errors)
+}
+
+func recoverFatal(fn func()) (msg string) {
+	defer func() {
+		if r := recover(); r != nil {
+			msg = string(r.(fatalMsg))
+		}
+	}()
+	fn()
+	return
+}
+
+func TestAssertManifest(t *testing.T) {
+	c := New(t)
+	c.Provide(&Service{}, &Client{})
+	c.ProvideByName("name", &name{"service"})
+	MustPopulate(t, c, nil)
+	AssertManifest(t, c, "testdata/manifest.golden")
+	if *update {
+		return
+	}
+
+	rt := &recordT{TB: t}
+	c = New(t)
+	c.Provide(&Service{}, &Client{}, &name{"unnamed"})
+	c.ProvideByName("name", &name{"service"})
+	MustPopulate(t, c, nil)
+	assert.False(t, AssertManifest(rt, c, "testdata/manifest.golden"))
+	assert.Len(t, rt.errors, 1)
+	assert.Contains(t, rt.errors[0], "+\t*injectgotest.name\n")
+	t.Log(rt.errors[0])
+
+	assert.False(t, AssertManifest(rt, c, "testdata/missing.golden"))
+}
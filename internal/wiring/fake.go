@@ -0,0 +1,196 @@
+package wiring
+
+import (
+	"bytes"
+	"fmt"
+	"go/types"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const injectgotestPath = injectgoPath + "/injectgotest"
+
+// fakeGenerator emits fake implementations of interfaces.
+type fakeGenerator struct {
+	pkg     *types.Package
+	imports map[string]string // path -> name
+	buf     bytes.Buffer
+}
+
+// GenerateFakes return source of fake implementations of interfaces required by inject fields
+// of structs in the package matched by pattern in dir, all structs of the package are used if structs is empty.
+// Generated code is in the same package and registers every fake to injectgotest in init,
+// so containers created with injectgotest.WithFakes are provided with them.
+func GenerateFakes(dir, pattern string, structs []string) ([]byte, error) {
+	pkgs, err := loadPackages(dir, pattern)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("pattern %s matches %d packages, expect 1", pattern, len(pkgs))
+	}
+	pkg := pkgs[0].Types
+
+	if len(structs) == 0 {
+		for _, name := range pkg.Scope().Names() {
+			if tn, ok := pkg.Scope().Lookup(name).(*types.TypeName); ok && isStruct(tn.Type()) {
+				structs = append(structs, name)
+			}
+		}
+	}
+	ifaces := make([]*types.Named, 0)
+	seen := make(map[string]bool)
+	for _, name := range structs {
+		tn, ok := pkg.Scope().Lookup(name).(*types.TypeName)
+		if !ok || !isStruct(tn.Type()) {
+			return nil, fmt.Errorf("struct %s not found in package %s", name, pkg.Path())
+		}
+		for _, f := range InjectFields(tn.Type()) {
+			named, ok := f.Type.(*types.Named)
+			if !ok || !types.IsInterface(named) || seen[named.String()] {
+				continue
+			}
+			if named.Underlying().(*types.Interface).NumMethods() == 0 || named.TypeParams().Len() > 0 {
+				continue
+			}
+			seen[named.String()] = true
+			ifaces = append(ifaces, named)
+		}
+	}
+	sort.Slice(ifaces, func(i, j int) bool {
+		return ifaces[i].String() < ifaces[j].String()
+	})
+
+	g := &fakeGenerator{
+		pkg:     pkg,
+		imports: map[string]string{injectgotestPath: "injectgotest", "sync": "sync"},
+	}
+	names := make(map[string]bool)
+	fakes := make([]string, 0, len(ifaces))
+	for _, iface := range ifaces {
+		name := "Fake" + exportName(iface.Obj().Name())
+		if names[name] {
+			name = "Fake" + exportName(iface.Obj().Pkg().Name()) + exportName(iface.Obj().Name())
+		}
+		names[name] = true
+		if err := g.fake(name, iface); err != nil {
+			return nil, err
+		}
+		fakes = append(fakes, name)
+	}
+
+	g.printf("func init() {\n")
+	for i, iface := range ifaces {
+		g.printf("injectgotest.RegisterFake((*%s)(nil), func() interface{} { return &%s{} })\n", g.typeString(iface), fakes[i])
+	}
+	g.printf("}\n")
+	return g.source()
+}
+
+func isStruct(tp types.Type) bool {
+	_, ok := tp.Underlying().(*types.Struct)
+	return ok
+}
+
+func exportName(s string) string {
+	r, n := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[n:]
+}
+
+func (g *fakeGenerator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// typeString return source of tp in generated package, and records imports it needs.
+func (g *fakeGenerator) typeString(tp types.Type) string {
+	return types.TypeString(tp, func(p *types.Package) string {
+		if p == g.pkg {
+			return ""
+		}
+		g.imports[p.Path()] = p.Name()
+		return p.Name()
+	})
+}
+
+// fake emits struct name implements iface. Calls of every method are recorded,
+// and method returns results of its programmable func field, or zero values if the field is nil.
+func (g *fakeGenerator) fake(name string, iface *types.Named) error {
+	it := iface.Underlying().(*types.Interface)
+	ifaceName := g.typeString(iface)
+	g.printf("// %s is a fake implementation of %s generated by injectgo fake.\n", name, ifaceName)
+	g.printf("type %s struct {\nmu sync.Mutex\n", name)
+	for i := 0; i < it.NumMethods(); i++ {
+		m := it.Method(i)
+		if !m.Exported() && m.Pkg() != g.pkg {
+			return fmt.Errorf("interface %s has unexported method %s, can not be implemented in package %s",
+				ifaceName, m.Name(), g.pkg.Path())
+		}
+		sig := m.Type().(*types.Signature)
+		g.printf("// %sFunc is called by %s if not nil, otherwise %s returns zero values.\n", m.Name(), m.Name(), m.Name())
+		g.printf("%sFunc func%s\n", m.Name(), strings.TrimPrefix(g.typeString(sig), "func"))
+		g.printf("calls%s [][]interface{}\n", exportName(m.Name()))
+	}
+	g.printf("}\n\n")
+
+	for i := 0; i < it.NumMethods(); i++ {
+		g.method(name, it.Method(i))
+	}
+	return nil
+}
+
+func (g *fakeGenerator) method(name string, m *types.Func) {
+	sig := m.Type().(*types.Signature)
+	params := make([]string, 0, sig.Params().Len())
+	args := make([]string, 0, sig.Params().Len())
+	for i := 0; i < sig.Params().Len(); i++ {
+		p := fmt.Sprintf("p%d", i)
+		tp := sig.Params().At(i).Type()
+		if sig.Variadic() && i == sig.Params().Len()-1 {
+			params = append(params, fmt.Sprintf("%s ...%s", p, g.typeString(tp.(*types.Slice).Elem())))
+			args = append(args, p+"...")
+			continue
+		}
+		params = append(params, fmt.Sprintf("%s %s", p, g.typeString(tp)))
+		args = append(args, p)
+	}
+	results := make([]string, 0, sig.Results().Len())
+	zeros := make([]string, 0, sig.Results().Len())
+	for i := 0; i < sig.Results().Len(); i++ {
+		results = append(results, g.typeString(sig.Results().At(i).Type()))
+		zeros = append(zeros, fmt.Sprintf("r%d", i))
+	}
+	resultSrc := strings.Join(results, ", ")
+	if len(results) > 1 {
+		resultSrc = "(" + resultSrc + ")"
+	}
+	recorded := make([]string, 0, len(args))
+	for _, a := range args {
+		recorded = append(recorded, strings.TrimSuffix(a, "..."))
+	}
+
+	calls := "calls" + exportName(m.Name())
+	g.printf("func (f *%s) %s(%s) %s {\n", name, m.Name(), strings.Join(params, ", "), resultSrc)
+	g.printf("f.mu.Lock()\nf.%s = append(f.%s, []interface{}{%s})\nfn := f.%sFunc\nf.mu.Unlock()\n",
+		calls, calls, strings.Join(recorded, ", "), m.Name())
+	g.printf("if fn != nil {\n")
+	if len(results) > 0 {
+		g.printf("return fn(%s)\n}\n", strings.Join(args, ", "))
+		for i := range zeros {
+			g.printf("var %s %s\n", zeros[i], results[i])
+		}
+		g.printf("return %s\n", strings.Join(zeros, ", "))
+	} else {
+		g.printf("fn(%s)\n}\n", strings.Join(args, ", "))
+	}
+	g.printf("}\n\n")
+
+	g.printf("// %sCalls return arguments of every %s call.\n", m.Name(), m.Name())
+	g.printf("func (f *%s) %sCalls() [][]interface{} {\n", name, m.Name())
+	g.printf("f.mu.Lock()\ndefer f.mu.Unlock()\nreturn append([][]interface{}(nil), f.%s...)\n}\n\n", calls)
+}
+
+func (g *fakeGenerator) source() ([]byte, error) {
+	return formatSource("injectgo fake", g.pkg.Name(), g.imports, g.buf.Bytes())
+}
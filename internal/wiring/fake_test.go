@@ -0,0 +1,27 @@
+package wiring
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateFakes(t *testing.T) {
+	src, err := GenerateFakes("", "./testdata/fake", nil)
+	assert.NoError(t, err)
+
+	// testdata/fake/fake_gen.go is generated by go generate and type checked by loading the package
+	expected, err := ioutil.ReadFile("testdata/fake/fake_gen.go")
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(src))
+
+	src, err = GenerateFakes("", "./testdata/fake", []string{"Worker"})
+	assert.NoError(t, err)
+	assert.Contains(t, string(src), "type FakeStore struct")
+	assert.NotContains(t, string(src), "FakeNotifier")
+
+	_, err = GenerateFakes("", "./testdata/fake", []string{"Store"})
+	assert.Error(t, err)
+	t.Log(err)
+}
@@ -0,0 +1,362 @@
+package wiring
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/printer"
+	"go/types"
+	"sort"
+	"strings"
+)
+
+// generator emits Go code which creates and populates bindings of a container without reflection.
+type generator struct {
+	c        *Container
+	params   map[types.Object]bool // parameters of setup function passed to generated function
+	imports  map[string]string     // path -> name
+	vars     map[*Binding]string
+	selected []*Binding
+	buf      bytes.Buffer
+}
+
+// Generate return source of function name which performs the same resolution as Populate:
+// create selected objects and call selected functions, assign inject fields,
+// call Init in dependency order and return a function calling Close in reverse order.
+// Parameters of the setup function except containers become parameters of the generated function.
+// It fails if any dependency is unfulfilled or cyclic, or bindings can not be generated.
+func Generate(c *Container, labels []string, name string) ([]byte, error) {
+	if problems := c.Check(labels); len(problems) > 0 {
+		msgs := make([]string, 0, len(problems))
+		for _, p := range problems {
+			msgs = append(msgs, p.String())
+		}
+		return nil, fmt.Errorf("check container %s:\n%s", c.ID, strings.Join(msgs, "\n"))
+	}
+
+	g := &generator{
+		c:       c,
+		params:  make(map[types.Object]bool),
+		imports: map[string]string{injectgoPath: "injectgo"},
+		vars:    make(map[*Binding]string),
+	}
+	for _, b := range c.Selected(labels) {
+		if b.Conditional {
+			return nil, fmt.Errorf("%s: function with conditions can not be generated", b.Pos)
+		}
+		if b.Transient {
+			return nil, fmt.Errorf("%s: transient function can not be generated", b.Pos)
+		}
+		if b.Request {
+			return nil, fmt.Errorf("%s: request scoped function can not be generated", b.Pos)
+		}
+		if err := g.checkInterfaceResult(b); err != nil {
+			return nil, err
+		}
+		g.selected = append(g.selected, b)
+	}
+	if err := g.generate(name); err != nil {
+		return nil, err
+	}
+	return g.source()
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// expr return source of e, after checking it only references package level identifiers,
+// parameters of setup function and identifiers declared in itself.
+func (g *generator) expr(e ast.Expr) (string, error) {
+	var err error
+	info := g.c.Pkg.TypesInfo
+	pkgScope := g.c.Pkg.Types.Scope()
+	ast.Inspect(e, func(n ast.Node) bool {
+		ident, ok := n.(*ast.Ident)
+		if !ok || err != nil {
+			return err == nil
+		}
+		obj := info.Uses[ident]
+		if obj == nil {
+			return true
+		}
+		if pkgName, ok := obj.(*types.PkgName); ok {
+			g.imports[pkgName.Imported().Path()] = pkgName.Name()
+			return true
+		}
+		scope := obj.Parent()
+		if scope == nil || scope == pkgScope || scope == types.Universe || g.params[obj] {
+			return true
+		}
+		if obj.Pos() >= e.Pos() && obj.Pos() < e.End() {
+			return true
+		}
+		err = fmt.Errorf("%s: expression references local %s, pass it as parameter of %s",
+			g.c.Pkg.Fset.Position(ident.Pos()), ident.Name, g.c.Func.Name.Name)
+		return true
+	})
+	if err != nil {
+		return "", err
+	}
+	buf := &bytes.Buffer{}
+	if err := printer.Fprint(buf, g.c.Pkg.Fset, e); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// signature return parameters of setup function except containers.
+func (g *generator) signature() (string, error) {
+	params := make([]string, 0)
+	for _, field := range g.c.Func.Type.Params.List {
+		tp := g.c.Pkg.TypesInfo.TypeOf(field.Type)
+		if isContainerType(tp) {
+			continue
+		}
+		names := make([]string, 0, len(field.Names))
+		for _, ident := range field.Names {
+			g.params[g.c.Pkg.TypesInfo.ObjectOf(ident)] = true
+			names = append(names, ident.Name)
+		}
+		tpSrc, err := g.expr(field.Type)
+		if err != nil {
+			return "", err
+		}
+		params = append(params, fmt.Sprintf("%s %s", strings.Join(names, ", "), tpSrc))
+	}
+	return strings.Join(params, ", "), nil
+}
+
+func isContainerType(tp types.Type) bool {
+	if p, ok := tp.(*types.Pointer); ok {
+		tp = p.Elem()
+	}
+	named, ok := tp.(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == injectgoPath && named.Obj().Name() == "Container"
+}
+
+func (g *generator) generate(name string) error {
+	sig, err := g.signature()
+	if err != nil {
+		return err
+	}
+	g.printf("// %s creates and populates objects provided in %s without reflection.\n", name, g.c.Func.Name.Name)
+	g.printf("// It returns a function to close objects in reverse order of initialization.\n")
+	g.printf("func %s(%s) (closeAll func() error, err error) {\n", name, sig)
+
+	// provided objects first, then functions like Populate does
+	ordered := make([]*Binding, 0, len(g.selected))
+	for _, b := range g.selected {
+		if b.Kind == KindObject {
+			ordered = append(ordered, b)
+		}
+	}
+	for _, b := range g.selected {
+		if b.Kind == KindFunction {
+			ordered = append(ordered, b)
+		}
+	}
+	for i, b := range ordered {
+		v := fmt.Sprintf("o%d", i)
+		g.vars[b] = v
+		src, err := g.expr(b.Expr)
+		if err != nil {
+			return err
+		}
+		if b.Kind == KindObject {
+			g.printf("%s := %s\n", v, src)
+			continue
+		}
+		if !b.ReturnsErr {
+			g.printf("%s := (%s)()\n", v, src)
+		} else {
+			g.imports["fmt"] = "fmt"
+			g.printf("%s, err := (%s)()\n", v, src)
+			g.printf("if err != nil {\nreturn nil, fmt.Errorf(%q, err)\n}\n", fmt.Sprintf("function %s error: %%v", b))
+		}
+		if b.Receiver != nil {
+			recv, err := g.receiver(b.Receiver)
+			if err != nil {
+				return err
+			}
+			g.printf("%s = %s\n", recv, v)
+		}
+	}
+
+	initOrder := make([]*Binding, 0, len(ordered))
+	visited := make(map[*Binding]bool)
+	for _, b := range ordered {
+		if err := g.populate(b, visited, &initOrder); err != nil {
+			return err
+		}
+	}
+
+	vars := make([]string, 0, len(initOrder))
+	for _, b := range initOrder {
+		vars = append(vars, g.vars[b])
+	}
+	g.printf("objects := []interface{}{%s}\n", strings.Join(vars, ", "))
+	g.printf(`closers := make([]injectgo.Closable, 0)
+closeAll = func() error {
+	for i := len(closers) - 1; i >= 0; i-- {
+		if err := closers[i].Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+for _, o := range objects {
+	if i, ok := o.(injectgo.Initializable); ok {
+		if err := i.Init(); err != nil {
+			// close objects already initialized
+			closeAll()
+			return nil, err
+		}
+	}
+	if c, ok := o.(injectgo.Closable); ok {
+		closers = append(closers, c)
+	}
+}
+return closeAll, nil
+}
+`)
+	return nil
+}
+
+// checkInterfaceResult return error if function b returns interface, and object returned by it has inject fields,
+// which are not populated by Populate either.
+func (g *generator) checkInterfaceResult(b *Binding) error {
+	if b.Kind != KindFunction || !types.IsInterface(b.Type) {
+		return nil
+	}
+	for _, tp := range g.returnedTypes(b.Expr) {
+		if len(InjectFields(tp)) > 0 {
+			return fmt.Errorf("%s: function returns interface %s, inject fields of %s are not populated, return %s instead",
+				b.Pos, TypeString(b.Type), TypeString(tp), TypeString(tp))
+		}
+	}
+	return nil
+}
+
+// returnedTypes return concrete types of first results returned by function e,
+// if e is a function literal or function declared in the package.
+func (g *generator) returnedTypes(e ast.Expr) []types.Type {
+	info := g.c.Pkg.TypesInfo
+	var body *ast.BlockStmt
+	switch fn := ast.Unparen(e).(type) {
+	case *ast.FuncLit:
+		body = fn.Body
+	case *ast.Ident:
+		body = g.funcBody(info.Uses[fn])
+	}
+	if body == nil {
+		return nil
+	}
+	tps := make([]types.Type, 0)
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.ReturnStmt:
+			if len(n.Results) == 0 {
+				return true
+			}
+			if tp := info.TypeOf(n.Results[0]); tp != nil && !types.IsInterface(tp) {
+				if _, ok := tp.(*types.Tuple); !ok {
+					tps = append(tps, tp)
+				}
+			}
+		}
+		return true
+	})
+	return tps
+}
+
+// funcBody return body of function obj declared in the package, nil if not found.
+func (g *generator) funcBody(obj types.Object) *ast.BlockStmt {
+	if _, ok := obj.(*types.Func); !ok {
+		return nil
+	}
+	for _, f := range g.c.Pkg.Syntax {
+		for _, decl := range f.Decls {
+			if fd, ok := decl.(*ast.FuncDecl); ok && g.c.Pkg.TypesInfo.Defs[fd.Name] == obj {
+				return fd.Body
+			}
+		}
+	}
+	return nil
+}
+
+// receiver return assignable expression of *Receiver.
+func (g *generator) receiver(e ast.Expr) (string, error) {
+	if u, ok := ast.Unparen(e).(*ast.UnaryExpr); ok && u.Op.String() == "&" {
+		return g.expr(u.X)
+	}
+	src, err := g.expr(e)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("*(%s)", src), nil
+}
+
+// populate assign inject fields of b after its dependencies, append b to initOrder when complete.
+func (g *generator) populate(b *Binding, visited map[*Binding]bool, initOrder *[]*Binding) error {
+	if visited[b] {
+		return nil
+	}
+	visited[b] = true
+	for _, f := range InjectFields(b.Type) {
+		if f.Tag == privateTag {
+			return fmt.Errorf("%s: private field %s of %s can not be generated", b.Pos, f.Name, TypeString(b.Type))
+		}
+		dep := Resolve(g.selected, f)
+		if err := g.populate(dep, visited, initOrder); err != nil {
+			return err
+		}
+		if !f.Exported && !g.samePackage(b.Type) {
+			return fmt.Errorf("%s: unexported field %s of %s can not be assigned", b.Pos, f.Name, TypeString(b.Type))
+		}
+		g.printf("%s.%s = %s\n", g.vars[b], f.Name, g.vars[dep])
+	}
+	*initOrder = append(*initOrder, b)
+	return nil
+}
+
+func (g *generator) samePackage(tp types.Type) bool {
+	if p, ok := tp.(*types.Pointer); ok {
+		tp = p.Elem()
+	}
+	named, ok := tp.(*types.Named)
+	return ok && named.Obj().Pkg() == g.c.Pkg.Types
+}
+
+func (g *generator) source() ([]byte, error) {
+	return formatSource("injectgo gen", g.c.Pkg.Name, g.imports, g.buf.Bytes())
+}
+
+// formatSource return formatted source of package pkgName generated by tool with imports and body.
+func formatSource(tool, pkgName string, imports map[string]string, body []byte) ([]byte, error) {
+	out := &bytes.Buffer{}
+	fmt.Fprintf(out, "// Code generated by %s; DO NOT EDIT.\n\npackage %s\n\nimport (\n", tool, pkgName)
+	paths := make([]string, 0, len(imports))
+	for path := range imports {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		name := imports[path]
+		if path == name || strings.HasSuffix(path, "/"+name) {
+			fmt.Fprintf(out, "\t%q\n", path)
+		} else {
+			fmt.Fprintf(out, "\t%s %q\n", name, path)
+		}
+	}
+	fmt.Fprintf(out, ")\n\n")
+	out.Write(body)
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %v\n%s", err, out.Bytes())
+	}
+	return src, nil
+}
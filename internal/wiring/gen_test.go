@@ -0,0 +1,113 @@
+package wiring
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/RivenZoo/injectgo"
+	"github.com/RivenZoo/injectgo/internal/wiring/testdata/gen"
+	"github.com/stretchr/testify/assert"
+)
+
+func loadGen(t *testing.T) map[string]*Container {
+	containers, err := Load("", "./testdata/gen")
+	assert.NoError(t, err)
+	ret := make(map[string]*Container)
+	for _, c := range containers {
+		ret[c.Func.Name.Name] = c
+	}
+	return ret
+}
+
+func TestGenerate(t *testing.T) {
+	containers := loadGen(t)
+
+	src, err := Generate(containers["Wire"], []string{"prod"}, "WireGenerated")
+	assert.NoError(t, err)
+	t.Logf("%s", src)
+
+	// testdata/gen/wire_gen.go is generated by go generate and type checked by Load
+	expected, err := ioutil.ReadFile("testdata/gen/wire_gen.go")
+	assert.NoError(t, err)
+	assert.Equal(t, string(expected), string(src))
+
+	_, err = Generate(containers["Local"], nil, "LocalGenerated")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "expression references local cfg")
+
+	assert.True(t, containers["Transient"].Bindings[2].Transient)
+	assert.Empty(t, containers["Transient"].Check(nil), "private field should not be checked by name")
+	_, err = Generate(containers["Transient"], nil, "TransientGenerated")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "transient function can not be generated")
+	assert.True(t, containers["Request"].Bindings[1].Request)
+	_, err = Generate(containers["Request"], nil, "RequestGenerated")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "request scoped function can not be generated")
+	_, err = Generate(containers["InterfaceResult"], nil, "InterfaceResultGenerated")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "function returns interface gen.Repo, inject fields of *gen.pgRepo are not populated")
+	_, err = Generate(containers["Private"], nil, "PrivateGenerated")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "private field Cache of *gen.Handler can not be generated")
+
+	_, err = Generate(containers["Wire"], []string{"test"}, "WireGenerated")
+	assert.Error(t, err, "should error because Repo is unfulfilled")
+	t.Log(err)
+}
+
+func TestWireGenerated(t *testing.T) {
+	gen.Events = nil
+	svc := &gen.Service{}
+	closeAll, err := gen.WireGenerated(svc, "127.0.0.1")
+	assert.NoError(t, err)
+	assert.Equal(t, "pg 127.0.0.1", svc.Repo.Find())
+	assert.NoError(t, closeAll())
+	assert.Equal(t, []string{
+		"init client 127.0.0.1", "init repo", "init client 127.0.0.1", "init service",
+		"close service", "close client", "close client",
+	}, gen.Events)
+
+	gen.Events = nil
+	closeAll, err = gen.WireGenerated(&gen.Service{}, "fail")
+	assert.Equal(t, gen.ErrFailed, err)
+	assert.Nil(t, closeAll)
+	assert.Equal(t, []string{
+		"init client fail", "init repo", "init client fail",
+		"close client", "close client",
+	}, gen.Events, "initialized objects should be closed if Init fails")
+}
+
+// TestWireRuntime checks injectgo initializes and closes objects like generated code,
+// objects of the same dependency depth may be initialized in other order.
+func TestWireRuntime(t *testing.T) {
+	gen.Events = nil
+	svc := &gen.Service{}
+	c := injectgo.NewContainer()
+	gen.Wire(c, svc, "127.0.0.1")
+	c.Populate(prodLabels{})
+	c.Close()
+	assert.ElementsMatch(t, []string{
+		"init client 127.0.0.1", "init repo", "init client 127.0.0.1", "init service",
+		"close service", "close client", "close client",
+	}, gen.Events)
+	assert.Equal(t, "init service", gen.Events[3])
+
+	gen.Events = nil
+	c = injectgo.NewContainer()
+	gen.Wire(c, &gen.Service{}, "fail")
+	assert.PanicsWithValue(t, gen.ErrFailed, func() {
+		c.Populate(prodLabels{})
+	})
+	assert.ElementsMatch(t, []string{
+		"init client fail", "init repo", "init client fail",
+		"close client", "close client",
+	}, gen.Events, "initialized objects should be closed if Init fails")
+	assert.Equal(t, []string{"close client", "close client"}, gen.Events[3:])
+}
+
+type prodLabels struct{}
+
+func (prodLabels) IsLabelAllowed(label string) bool {
+	return label == "prod"
+}
@@ -0,0 +1,83 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/RivenZoo/injectgo"
+)
+
+type Client struct{}
+
+type Logger interface {
+	Log(string)
+}
+
+type stdLogger struct{}
+
+func (l *stdLogger) Log(s string) {
+	fmt.Println(s)
+}
+
+type Service struct {
+	Client *Client `inject:""`
+	Cache  *Client `inject:"cache"`
+	Logger Logger  `inject:""`
+}
+
+type CyclicA struct {
+	B *CyclicB `inject:""`
+}
+
+type CyclicB struct {
+	A *CyclicA `inject:""`
+}
+
+const cacheName = "cache"
+
+func NewLogger() (Logger, error) {
+	return &stdLogger{}, nil
+}
+
+func Complete() {
+	c := injectgo.NewContainer()
+	c.Provide(&Service{}, &Client{})
+	c.ProvideFuncByName(cacheName, injectgo.InjectFunc{
+		Fn: func() *Client { return &Client{} },
+	})
+	c.ProvideFunc(injectgo.InjectFunc{Fn: NewLogger, Label: "prod"})
+	c.Populate(nil)
+}
+
+func Unfulfilled() {
+	c := injectgo.NewContainer()
+	c.Provide(&Service{})
+	c.Populate(nil)
+}
+
+func Cyclic() {
+	c := injectgo.NewContainer()
+	c.Provide(&CyclicA{}, &CyclicB{})
+	c.Populate(nil)
+}
+
+func Scoped() {
+	c := injectgo.NewContainer()
+	scope := injectgo.ScopeTransient
+	c.Provide(&Service{})
+	c.ProvideFunc(injectgo.InjectFunc{Fn: func() *Client { return &Client{} }, Scope: injectgo.ScopeTransient})
+	c.ProvideFunc(injectgo.InjectFunc{Fn: NewLogger, Scope: scope})
+	c.Populate(nil)
+}
+
+type fileLogger struct{}
+
+func (l *fileLogger) Log(s string) {}
+
+func Duplicate() {
+	c := injectgo.NewContainer()
+	c.Provide(&Service{}, &Client{}, &Client{}, &stdLogger{}, &fileLogger{})
+	c.ProvideFuncByName(cacheName, injectgo.InjectFunc{
+		Fn: func() *Client { return &Client{} },
+	})
+	c.Populate(nil)
+}
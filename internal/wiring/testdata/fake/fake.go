@@ -0,0 +1,32 @@
+package fake
+
+import (
+	"context"
+	"io"
+)
+
+//go:generate go run github.com/RivenZoo/injectgo/cmd/injectgo fake -out fake_gen.go
+
+type Store interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Put(ctx context.Context, key string, value []byte) error
+}
+
+type Notifier interface {
+	Notify(format string, args ...interface{})
+}
+
+type Service struct {
+	Store    Store     `inject:""`
+	Notifier Notifier  `inject:"notifier"`
+	Out      io.Writer `inject:""`
+	Config   *Config   `inject:""`
+}
+
+type Worker struct {
+	Store Store `inject:""`
+}
+
+type Config struct {
+	Addr string
+}
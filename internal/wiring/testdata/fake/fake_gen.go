@@ -0,0 +1,119 @@
+// Code generated by injectgo fake; DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+	"github.com/RivenZoo/injectgo/injectgotest"
+	"io"
+	"sync"
+)
+
+// FakeNotifier is a fake implementation of Notifier generated by injectgo fake.
+type FakeNotifier struct {
+	mu sync.Mutex
+	// NotifyFunc is called by Notify if not nil, otherwise Notify returns zero values.
+	NotifyFunc  func(format string, args ...interface{})
+	callsNotify [][]interface{}
+}
+
+func (f *FakeNotifier) Notify(p0 string, p1 ...interface{}) {
+	f.mu.Lock()
+	f.callsNotify = append(f.callsNotify, []interface{}{p0, p1})
+	fn := f.NotifyFunc
+	f.mu.Unlock()
+	if fn != nil {
+		fn(p0, p1...)
+	}
+}
+
+// NotifyCalls return arguments of every Notify call.
+func (f *FakeNotifier) NotifyCalls() [][]interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([][]interface{}(nil), f.callsNotify...)
+}
+
+// FakeStore is a fake implementation of Store generated by injectgo fake.
+type FakeStore struct {
+	mu sync.Mutex
+	// GetFunc is called by Get if not nil, otherwise Get returns zero values.
+	GetFunc  func(ctx context.Context, key string) ([]byte, error)
+	callsGet [][]interface{}
+	// PutFunc is called by Put if not nil, otherwise Put returns zero values.
+	PutFunc  func(ctx context.Context, key string, value []byte) error
+	callsPut [][]interface{}
+}
+
+func (f *FakeStore) Get(p0 context.Context, p1 string) ([]byte, error) {
+	f.mu.Lock()
+	f.callsGet = append(f.callsGet, []interface{}{p0, p1})
+	fn := f.GetFunc
+	f.mu.Unlock()
+	if fn != nil {
+		return fn(p0, p1)
+	}
+	var r0 []byte
+	var r1 error
+	return r0, r1
+}
+
+// GetCalls return arguments of every Get call.
+func (f *FakeStore) GetCalls() [][]interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([][]interface{}(nil), f.callsGet...)
+}
+
+func (f *FakeStore) Put(p0 context.Context, p1 string, p2 []byte) error {
+	f.mu.Lock()
+	f.callsPut = append(f.callsPut, []interface{}{p0, p1, p2})
+	fn := f.PutFunc
+	f.mu.Unlock()
+	if fn != nil {
+		return fn(p0, p1, p2)
+	}
+	var r0 error
+	return r0
+}
+
+// PutCalls return arguments of every Put call.
+func (f *FakeStore) PutCalls() [][]interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([][]interface{}(nil), f.callsPut...)
+}
+
+// FakeWriter is a fake implementation of io.Writer generated by injectgo fake.
+type FakeWriter struct {
+	mu sync.Mutex
+	// WriteFunc is called by Write if not nil, otherwise Write returns zero values.
+	WriteFunc  func(p []byte) (n int, err error)
+	callsWrite [][]interface{}
+}
+
+func (f *FakeWriter) Write(p0 []byte) (int, error) {
+	f.mu.Lock()
+	f.callsWrite = append(f.callsWrite, []interface{}{p0})
+	fn := f.WriteFunc
+	f.mu.Unlock()
+	if fn != nil {
+		return fn(p0)
+	}
+	var r0 int
+	var r1 error
+	return r0, r1
+}
+
+// WriteCalls return arguments of every Write call.
+func (f *FakeWriter) WriteCalls() [][]interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([][]interface{}(nil), f.callsWrite...)
+}
+
+func init() {
+	injectgotest.RegisterFake((*Notifier)(nil), func() interface{} { return &FakeNotifier{} })
+	injectgotest.RegisterFake((*Store)(nil), func() interface{} { return &FakeStore{} })
+	injectgotest.RegisterFake((*io.Writer)(nil), func() interface{} { return &FakeWriter{} })
+}
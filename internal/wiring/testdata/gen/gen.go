@@ -0,0 +1,119 @@
+package gen
+
+import (
+	"errors"
+
+	"github.com/RivenZoo/injectgo"
+)
+
+//go:generate go run github.com/RivenZoo/injectgo/cmd/injectgo gen -func Wire -labels prod -out wire_gen.go
+
+// Events records Init and Close calls.
+var Events []string
+
+type Config struct {
+	Addr string
+}
+
+type Client struct {
+	Config *Config `inject:""`
+}
+
+func (c *Client) Init() error {
+	Events = append(Events, "init client "+c.Config.Addr)
+	return nil
+}
+
+func (c *Client) Close() error {
+	Events = append(Events, "close client")
+	return nil
+}
+
+type Repo interface {
+	Find() string
+}
+
+type pgRepo struct {
+	Client *Client `inject:""`
+}
+
+func (r *pgRepo) Find() string {
+	return "pg " + r.Client.Config.Addr
+}
+
+func (r *pgRepo) Init() error {
+	Events = append(Events, "init repo")
+	return nil
+}
+
+type Service struct {
+	Repo  Repo    `inject:""`
+	Admin *Client `inject:"admin"`
+}
+
+func (s *Service) Init() error {
+	if s.Admin.Config.Addr == "fail" {
+		return ErrFailed
+	}
+	Events = append(Events, "init service")
+	return nil
+}
+
+func (s *Service) Close() error {
+	Events = append(Events, "close service")
+	return nil
+}
+
+var ErrFailed = errors.New("failed")
+
+var repo *pgRepo
+
+func NewRepo() (*pgRepo, error) {
+	return &pgRepo{}, nil
+}
+
+func newInterfaceRepo() (Repo, error) {
+	return &pgRepo{}, nil
+}
+
+func Wire(c *injectgo.Container, svc *Service, addr string) {
+	c.Provide(svc, &Client{}, &Config{Addr: addr})
+	c.ProvideFunc(injectgo.InjectFunc{Fn: NewRepo, Receiver: &repo, Label: "prod"})
+	c.ProvideFunc(injectgo.InjectFunc{Fn: func() (Repo, error) { return nil, ErrFailed }, Label: "dev"})
+	c.ProvideFuncByName("admin", injectgo.InjectFunc{
+		Fn: func() *Client { return &Client{} },
+	})
+}
+
+func InterfaceResult(c *injectgo.Container, addr string) {
+	c.Provide(&Config{Addr: addr}, &Client{})
+	c.ProvideFunc(injectgo.InjectFunc{Fn: newInterfaceRepo})
+}
+
+type Handler struct {
+	Client *Client `inject:""`
+	Cache  *Cache  `inject:"private"`
+}
+
+type Cache struct {
+	Config *Config `inject:""`
+}
+
+func Transient(c *injectgo.Container, addr string) {
+	c.Provide(&Config{Addr: addr}, &Handler{})
+	c.ProvideFunc(injectgo.InjectFunc{Fn: func() *Client { return &Client{} }, Scope: injectgo.ScopeTransient})
+}
+
+func Request(c *injectgo.Container, addr string) {
+	c.Provide(&Config{Addr: addr})
+	c.ProvideFunc(injectgo.InjectFunc{Fn: func() *Client { return &Client{} }, Scope: injectgo.ScopeRequest})
+}
+
+func Private(c *injectgo.Container, addr string) {
+	c.Provide(&Config{Addr: addr}, &Handler{}, &Client{})
+}
+
+func Local(c *injectgo.Container) {
+	cfg := &Config{}
+	c.Provide(cfg)
+}
@@ -0,0 +1,533 @@
+// Package wiring finds containers and their provided objects and functions in Go source,
+// and resolves inject fields statically like injectgo does at runtime.
+package wiring
+
+import (
+	"fmt"
+	"go/ast"
+	"go/constant"
+	"go/token"
+	"go/types"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/RivenZoo/injectgo"
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	injectTag    = "inject"
+	privateTag   = "private"
+	injectgoPath = "github.com/RivenZoo/injectgo"
+)
+
+// Binding kinds.
+const (
+	KindObject   = "object"
+	KindFunction = "function"
+)
+
+// Binding is an object or function provided to a container.
+type Binding struct {
+	Name        string
+	Label       string
+	Kind        string
+	Type        types.Type // object type or function return type
+	Expr        ast.Expr   // object expression, or Fn of InjectFunc
+	Receiver    ast.Expr   // Receiver of InjectFunc, nil if not set
+	ReturnsErr  bool       // Fn returns (T, error)
+	Conditional bool       // InjectFunc has conditions
+	Transient   bool       // InjectFunc has transient scope
+	Request     bool       // InjectFunc has request scope
+	Pos         token.Position
+}
+
+// Container is a container variable and all objects and functions provided to it.
+type Container struct {
+	ID       string // package path, function and variable name
+	Pkg      *packages.Package
+	Func     *ast.FuncDecl
+	Pos      token.Position
+	Bindings []*Binding
+	Problems []Problem // calls which can not be analyzed
+}
+
+// Problem is an error found at Pos.
+type Problem struct {
+	Pos     token.Position
+	Message string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("%s: %s", p.Pos, p.Message)
+}
+
+// Load load packages matched by patterns in dir and return containers found in them.
+func Load(dir string, patterns ...string) ([]*Container, error) {
+	pkgs, err := loadPackages(dir, patterns...)
+	if err != nil {
+		return nil, err
+	}
+
+	containers := make([]*Container, 0)
+	for _, pkg := range pkgs {
+		containers = append(containers, findContainers(pkg)...)
+	}
+	return containers, nil
+}
+
+func loadPackages(dir string, patterns ...string) ([]*packages.Package, error) {
+	cfg := &packages.Config{
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedSyntax |
+			packages.NeedTypes | packages.NeedTypesInfo | packages.NeedImports | packages.NeedDeps,
+		Dir: dir,
+	}
+	pkgs, err := packages.Load(cfg, patterns...)
+	if err != nil {
+		return nil, err
+	}
+	if n := packages.PrintErrors(pkgs); n > 0 {
+		return nil, fmt.Errorf("load packages: %d errors", n)
+	}
+	return pkgs, nil
+}
+
+func findContainers(pkg *packages.Package) []*Container {
+	byObj := make(map[types.Object]*Container)
+	containers := make([]*Container, 0)
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok || fd.Body == nil {
+				continue
+			}
+			ast.Inspect(fd.Body, func(n ast.Node) bool {
+				call, ok := n.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				sel, ok := call.Fun.(*ast.SelectorExpr)
+				if !ok || !isContainerMethod(pkg.TypesInfo, sel) {
+					return true
+				}
+				recv, ok := sel.X.(*ast.Ident)
+				if !ok {
+					return true
+				}
+				obj := pkg.TypesInfo.ObjectOf(recv)
+				c, ok := byObj[obj]
+				if !ok {
+					c = &Container{
+						ID:   fmt.Sprintf("%s.%s.%s", pkg.PkgPath, fd.Name.Name, recv.Name),
+						Pkg:  pkg,
+						Func: fd,
+						Pos:  pkg.Fset.Position(obj.Pos()),
+					}
+					byObj[obj] = c
+					containers = append(containers, c)
+				}
+				c.addCall(sel.Sel.Name, call)
+				return true
+			})
+		}
+	}
+	return containers
+}
+
+func isContainerMethod(info *types.Info, sel *ast.SelectorExpr) bool {
+	s, ok := info.Selections[sel]
+	if !ok || s.Kind() != types.MethodVal {
+		return false
+	}
+	recv := s.Recv()
+	if p, ok := recv.(*types.Pointer); ok {
+		recv = p.Elem()
+	}
+	named, ok := recv.(*types.Named)
+	if !ok {
+		return false
+	}
+	obj := named.Obj()
+	return obj.Pkg() != nil && obj.Pkg().Path() == injectgoPath && obj.Name() == "Container"
+}
+
+func (c *Container) position(n ast.Node) token.Position {
+	return c.Pkg.Fset.Position(n.Pos())
+}
+
+func (c *Container) problem(n ast.Node, format string, args ...interface{}) {
+	c.Problems = append(c.Problems, Problem{Pos: c.position(n), Message: fmt.Sprintf(format, args...)})
+}
+
+// stringConst return value of constant string expression.
+func (c *Container) stringConst(e ast.Expr) (string, bool) {
+	tv, ok := c.Pkg.TypesInfo.Types[e]
+	if !ok || tv.Value == nil || tv.Value.Kind() != constant.String {
+		c.problem(e, "argument should be constant string")
+		return "", false
+	}
+	return constant.StringVal(tv.Value), true
+}
+
+func (c *Container) addCall(method string, call *ast.CallExpr) {
+	if call.Ellipsis.IsValid() {
+		c.problem(call, "variadic argument of %s can not be analyzed", method)
+		return
+	}
+	args := call.Args
+	switch method {
+	case "Provide":
+		c.addObjects("", "", args)
+	case "ProvideWithLabel":
+		if label, ok := c.stringConst(args[0]); ok {
+			c.addObjects("", label, args[1:])
+		}
+	case "ProvideByName":
+		if name, ok := c.stringConst(args[0]); ok {
+			c.addObjects(name, "", args[1:])
+		}
+	case "ProvideByNameWithLabel":
+		name, ok1 := c.stringConst(args[0])
+		label, ok2 := c.stringConst(args[1])
+		if ok1 && ok2 {
+			c.addObjects(name, label, args[2:])
+		}
+	case "ProvideFunc":
+		for _, arg := range args {
+			c.addFunc("", arg)
+		}
+	case "ProvideFuncByName":
+		if name, ok := c.stringConst(args[0]); ok {
+			c.addFunc(name, args[1])
+		}
+	}
+}
+
+func (c *Container) addObjects(name, label string, args []ast.Expr) {
+	for _, arg := range args {
+		c.Bindings = append(c.Bindings, &Binding{
+			Name:  name,
+			Label: label,
+			Kind:  KindObject,
+			Type:  c.Pkg.TypesInfo.TypeOf(arg),
+			Expr:  arg,
+			Pos:   c.position(arg),
+		})
+	}
+}
+
+func (c *Container) addFunc(name string, arg ast.Expr) {
+	lit, ok := ast.Unparen(arg).(*ast.CompositeLit)
+	if !ok {
+		c.problem(arg, "InjectFunc should be composite literal to be analyzed")
+		return
+	}
+	b := &Binding{Name: name, Kind: KindFunction, Pos: c.position(arg)}
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key, ok := kv.Key.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		switch key.Name {
+		case "Fn":
+			b.Expr = kv.Value
+		case "Label":
+			b.Label, _ = c.stringConst(kv.Value)
+		case "Receiver":
+			b.Receiver = kv.Value
+		case "Conditions":
+			b.Conditional = true
+		case "Scope":
+			switch c.scopeName(kv.Value) {
+			case "ScopeSingleton":
+			case "ScopeTransient":
+				b.Transient = true
+			case "ScopeRequest":
+				b.Request = true
+			default:
+				c.problem(kv.Value, "InjectFunc Scope should be injectgo Scope constant")
+			}
+		}
+	}
+	if b.Expr == nil {
+		c.problem(arg, "InjectFunc should set Fn")
+		return
+	}
+	sig, ok := c.Pkg.TypesInfo.TypeOf(b.Expr).Underlying().(*types.Signature)
+	if !ok || sig.Params().Len() != 0 || sig.Results().Len() == 0 || sig.Results().Len() > 2 {
+		c.problem(b.Expr, "InjectFunc Fn should be func() T or func() (T, error)")
+		return
+	}
+	b.Type = sig.Results().At(0).Type()
+	b.ReturnsErr = sig.Results().Len() == 2
+	c.Bindings = append(c.Bindings, b)
+}
+
+// scopeName return name of injectgo Scope constant e refers to, empty if e is not one of them.
+func (c *Container) scopeName(e ast.Expr) string {
+	var id *ast.Ident
+	switch x := ast.Unparen(e).(type) {
+	case *ast.Ident:
+		id = x
+	case *ast.SelectorExpr:
+		id = x.Sel
+	default:
+		return ""
+	}
+	obj, ok := c.Pkg.TypesInfo.Uses[id].(*types.Const)
+	if !ok || obj.Pkg() == nil || obj.Pkg().Path() != injectgoPath {
+		return ""
+	}
+	return obj.Name()
+}
+
+// Field is an inject field of a struct.
+type Field struct {
+	Name     string
+	Tag      string
+	Type     types.Type
+	Exported bool
+}
+
+// InjectFields return inject fields of struct tp or tp points to.
+func InjectFields(tp types.Type) []Field {
+	if p, ok := tp.Underlying().(*types.Pointer); ok {
+		tp = p.Elem()
+	}
+	st, ok := tp.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+	fields := make([]Field, 0)
+	for i := 0; i < st.NumFields(); i++ {
+		tag, ok := reflect.StructTag(st.Tag(i)).Lookup(injectTag)
+		if !ok {
+			continue
+		}
+		f := st.Field(i)
+		fields = append(fields, Field{Name: f.Name(), Tag: tag, Type: f.Type(), Exported: f.Exported()})
+	}
+	return fields
+}
+
+// Selected return bindings selected by labels, all bindings are selected if labels is nil.
+func (c *Container) Selected(labels []string) []*Binding {
+	ret := make([]*Binding, 0, len(c.Bindings))
+	for _, b := range c.Bindings {
+		if labels == nil || b.Label == "" || contains(labels, b.Label) {
+			ret = append(ret, b)
+		}
+	}
+	return ret
+}
+
+func contains(s []string, v string) bool {
+	for i := range s {
+		if s[i] == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Resolve return binding satisfies field like injectgo does: binding provided by tag name,
+// or the last unnamed binding with identical type, which replaces earlier ones like injectgo,
+// or unnamed binding assignable to field type. It returns nil if bindings of several types are assignable,
+// injectgo picks any of them.
+func Resolve(bindings []*Binding, f Field) *Binding {
+	if f.Tag != "" {
+		for _, b := range bindings {
+			if b.Name == f.Tag {
+				return b
+			}
+		}
+		return nil
+	}
+	var ret *Binding
+	for _, b := range bindings {
+		if b.Name == "" && types.Identical(b.Type, f.Type) {
+			ret = b
+		}
+	}
+	if ret != nil {
+		return ret
+	}
+	if candidates := assignable(bindings, f); len(candidates) == 1 {
+		return candidates[0]
+	}
+	return nil
+}
+
+// assignable return the last unnamed binding of each type assignable to field type.
+func assignable(bindings []*Binding, f Field) []*Binding {
+	ret := make([]*Binding, 0)
+	for _, b := range bindings {
+		if b.Name != "" || !types.AssignableTo(b.Type, f.Type) {
+			continue
+		}
+		replaced := false
+		for i := range ret {
+			if types.Identical(ret[i].Type, b.Type) {
+				ret[i] = b
+				replaced = true
+			}
+		}
+		if !replaced {
+			ret = append(ret, b)
+		}
+	}
+	return ret
+}
+
+// TypeString return type string qualified by package name, like reflect.Type.String.
+func TypeString(tp types.Type) string {
+	return types.TypeString(tp, func(p *types.Package) string {
+		return p.Name()
+	})
+}
+
+// Check report unfulfilled names and types, ambiguous types and type level cyclic of bindings selected by labels,
+// the same way injectgo checks before populate.
+func (c *Container) Check(labels []string) []Problem {
+	problems := append([]Problem{}, c.Problems...)
+	selected := c.Selected(labels)
+	for _, b := range selected {
+		for _, f := range InjectFields(b.Type) {
+			// object of private field is created when populating
+			if f.Tag == privateTag || Resolve(selected, f) != nil {
+				continue
+			}
+			if f.Tag != "" {
+				problems = append(problems, Problem{Pos: b.Pos, Message: fmt.Sprintf(
+					"container %s: unfulfilled name %q of field %s.%s", c.ID, f.Tag, TypeString(b.Type), f.Name)})
+				continue
+			}
+			if candidates := assignable(selected, f); len(candidates) > 1 {
+				names := make([]string, 0, len(candidates))
+				for _, cb := range candidates {
+					names = append(names, TypeString(cb.Type))
+				}
+				problems = append(problems, Problem{Pos: b.Pos, Message: fmt.Sprintf(
+					"container %s: ambiguous type %s of field %s.%s, assignable types %s",
+					c.ID, TypeString(f.Type), TypeString(b.Type), f.Name, strings.Join(names, ", "))})
+				continue
+			}
+			problems = append(problems, Problem{Pos: b.Pos, Message: fmt.Sprintf(
+				"container %s: unfulfilled type %s of field %s.%s", c.ID, TypeString(f.Type), TypeString(b.Type), f.Name)})
+		}
+	}
+	if path := detectCyclic(selected); path != nil {
+		problems = append(problems, Problem{Pos: c.Pos, Message: fmt.Sprintf(
+			"container %s: dependency cyclic detected, cyclic path %s", c.ID, path)})
+	}
+	return problems
+}
+
+// detectCyclic detect cyclic of struct types through pointer inject fields like injectgo cyclicDetector.
+func detectCyclic(bindings []*Binding) cyclicPath {
+	deps := make(map[string][]string)
+	roots := make([]string, 0)
+	for _, b := range bindings {
+		tp := b.Type
+		if p, ok := tp.Underlying().(*types.Pointer); ok {
+			tp = p.Elem()
+		}
+		if _, ok := tp.Underlying().(*types.Struct); !ok {
+			continue
+		}
+		key := TypeString(tp)
+		if _, ok := deps[key]; ok {
+			continue
+		}
+		roots = append(roots, key)
+		fieldTypes := make([]string, 0)
+		for _, f := range InjectFields(tp) {
+			if p, ok := f.Type.Underlying().(*types.Pointer); ok {
+				fieldTypes = append(fieldTypes, TypeString(p.Elem()))
+			}
+		}
+		deps[key] = fieldTypes
+	}
+	for _, root := range roots {
+		if path := traverse(deps, root, nil); path != nil {
+			return path
+		}
+	}
+	return nil
+}
+
+type cyclicPath []string
+
+func (p cyclicPath) String() string {
+	s := "["
+	for i := range p {
+		if i > 0 {
+			s += " -> "
+		}
+		s += p[i]
+	}
+	return s + "]"
+}
+
+func traverse(deps map[string][]string, tp string, path cyclicPath) cyclicPath {
+	for i := range path {
+		if path[i] == tp {
+			return append(append(cyclicPath{}, path[i:]...), tp)
+		}
+	}
+	path = append(path, tp)
+	for _, next := range deps[tp] {
+		if _, ok := deps[next]; !ok {
+			continue
+		}
+		if cp := traverse(deps, next, path); cp != nil {
+			return cp
+		}
+	}
+	return nil
+}
+
+// String return position of binding with quoted name if it is provided by name.
+func (b *Binding) String() string {
+	if b.Name != "" {
+		return fmt.Sprintf("%s(%s)", strconv.Quote(b.Name), TypeString(b.Type))
+	}
+	return TypeString(b.Type)
+}
+
+// Graph return the declared graph of bindings selected by labels.
+func (c *Container) Graph(labels []string) *injectgo.Graph {
+	selected := c.Selected(labels)
+	ids := make(map[*Binding]string, len(selected))
+	g := &injectgo.Graph{Nodes: make([]injectgo.Node, 0, len(selected)), Edges: make([]injectgo.Edge, 0)}
+	for i, b := range selected {
+		ids[b] = fmt.Sprintf("n%d", i)
+		kind := injectgo.NodeObject
+		if b.Kind == KindFunction {
+			kind = injectgo.NodeFunction
+		}
+		g.Nodes = append(g.Nodes, injectgo.Node{
+			ID:    ids[b],
+			Kind:  kind,
+			Type:  TypeString(b.Type),
+			Name:  b.Name,
+			Label: b.Label,
+			Site:  b.Pos.String(),
+		})
+	}
+	for _, b := range selected {
+		for _, f := range InjectFields(b.Type) {
+			e := injectgo.Edge{From: ids[b], Field: f.Name, Tag: f.Tag}
+			if to := Resolve(selected, f); to != nil {
+				e.To = ids[to]
+			}
+			g.Edges = append(g.Edges, e)
+		}
+	}
+	return g
+}
@@ -0,0 +1,79 @@
+package wiring
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func loadApp(t *testing.T) map[string]*Container {
+	containers, err := Load("", "./testdata/app")
+	assert.NoError(t, err)
+	ret := make(map[string]*Container)
+	for _, c := range containers {
+		ret[c.ID[strings.LastIndex(c.ID, "/")+1:]] = c
+	}
+	return ret
+}
+
+func TestLoad(t *testing.T) {
+	containers := loadApp(t)
+	assert.Len(t, containers, 5)
+
+	c := containers["app.Complete.c"]
+	assert.NotNil(t, c)
+	assert.Len(t, c.Bindings, 4)
+	assert.Equal(t, "*app.Service", TypeString(c.Bindings[0].Type))
+	assert.Equal(t, "cache", c.Bindings[2].Name)
+	assert.Equal(t, KindFunction, c.Bindings[2].Kind)
+	assert.Equal(t, "app.Logger", TypeString(c.Bindings[3].Type))
+	assert.Equal(t, "prod", c.Bindings[3].Label)
+	assert.True(t, c.Bindings[3].ReturnsErr)
+
+	c = containers["app.Scoped.c"]
+	assert.Len(t, c.Bindings, 3)
+	assert.True(t, c.Bindings[1].Transient)
+	assert.False(t, c.Bindings[2].Transient)
+	assert.Len(t, c.Problems, 1)
+	assert.Contains(t, c.Problems[0].Message, "Scope should be injectgo Scope constant")
+}
+
+func TestCheck(t *testing.T) {
+	containers := loadApp(t)
+
+	assert.Empty(t, containers["app.Complete.c"].Check(nil))
+	problems := containers["app.Complete.c"].Check([]string{"dev"})
+	assert.Len(t, problems, 1)
+	assert.Contains(t, problems[0].Message, "unfulfilled type app.Logger of field *app.Service.Logger")
+
+	problems = containers["app.Unfulfilled.c"].Check(nil)
+	assert.Len(t, problems, 3)
+	assert.Contains(t, problems[1].Message, `unfulfilled name "cache"`)
+	for _, p := range problems {
+		t.Log(p)
+	}
+
+	c := containers["app.Duplicate.c"]
+	problems = c.Check(nil)
+	assert.Len(t, problems, 1)
+	assert.Contains(t, problems[0].Message, "ambiguous type app.Logger of field *app.Service.Logger, "+
+		"assignable types *app.stdLogger, *app.fileLogger")
+	client := InjectFields(c.Bindings[0].Type)[0]
+	assert.True(t, Resolve(c.Bindings, client) == c.Bindings[2], "last binding of the same type should be resolved")
+
+	problems = containers["app.Cyclic.c"].Check(nil)
+	assert.Len(t, problems, 1)
+	assert.Contains(t, problems[0].Message, "dependency cyclic detected")
+	t.Log(problems[0])
+}
+
+func TestGraph(t *testing.T) {
+	g := loadApp(t)["app.Complete.c"].Graph(nil)
+	assert.Len(t, g.Nodes, 4)
+	assert.Len(t, g.Edges, 3)
+	assert.Equal(t, "n1", g.Edges[0].To)
+	assert.Equal(t, "n2", g.Edges[1].To)
+	assert.Equal(t, "n3", g.Edges[2].To)
+	assert.Contains(t, g.Nodes[0].Site, "app.go:")
+}
@@ -0,0 +1,163 @@
+package injectgo
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// PluginLoader open Go plugin at path and return module exported by it.
+type PluginLoader func(path string) (*Module, error)
+
+var (
+	pluginMu     sync.RWMutex
+	pluginLoader PluginLoader
+)
+
+// RegisterPluginLoader set loader used by LoadPlugin, package injectgo/plugin registers it in init.
+// Loader is not registered by injectgo, so programs not loading plugins do not link package plugin and cgo.
+func RegisterPluginLoader(l PluginLoader) {
+	pluginMu.Lock()
+	defer pluginMu.Unlock()
+	pluginLoader = l
+}
+
+// LoadPlugin install module exported by Go plugin at path like Install, file path is recorded as site of its bindings.
+// Package injectgo/plugin should be imported to register loader, otherwise it returns error.
+// Types of module objects, functions and their inject fields should be the same types of c,
+// a type of the same name but not identical, eg. plugin built with other version of shared package, is an error.
+// Returned error names the plugin file.
+func (c *Container) LoadPlugin(path string) (err error) {
+	pluginMu.RLock()
+	load := pluginLoader
+	pluginMu.RUnlock()
+	if load == nil {
+		return fmt.Errorf("load plugin %s error: no loader registered, import github.com/RivenZoo/injectgo/plugin", path)
+	}
+	m, err := load(path)
+	if err != nil {
+		return fmt.Errorf("load plugin %s error: %w", path, err)
+	}
+	if m == nil {
+		return fmt.Errorf("load plugin %s error: nil module", path)
+	}
+	if err := c.checkPluginTypes(m); err != nil {
+		return fmt.Errorf("load plugin %s error: %v", path, err)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			if e, ok := r.(error); ok {
+				err = fmt.Errorf("load plugin %s error: %w", path, e)
+				return
+			}
+			err = fmt.Errorf("load plugin %s error: %v", path, r)
+		}
+	}()
+	c.installModules(path, m)
+	return nil
+}
+
+// checkPluginTypes return error if a type used by m has the same name of a type used by c but is not identical.
+func (c *Container) checkPluginTypes(m *Module) error {
+	host := make(namedTypes)
+	c.mu.RLock()
+	for _, pv := range c.unnamedValues {
+		host.add(pv.value.Type())
+	}
+	for _, pv := range c.namedValues {
+		host.add(pv.value.Type())
+	}
+	for _, ifn := range c.unnamedFunctions {
+		host.add(ifn.returnType())
+	}
+	for _, ifn := range c.namedFunctions {
+		host.add(ifn.returnType())
+	}
+	c.mu.RUnlock()
+
+	plugin := make(namedTypes)
+	plugin.addModule(m, make(map[*Module]bool))
+	problems := make([]string, 0)
+	for name, tp := range plugin {
+		if ht, ok := host[name]; ok && ht != tp {
+			problems = append(problems, fmt.Sprintf("type %v is not the type of the program, "+
+				"plugin should be built with the same version of package %s", tp, name.pkgPath))
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return fmt.Errorf("incompatible types:\n%s", strings.Join(problems, "\n"))
+	}
+	return nil
+}
+
+type typeName struct {
+	pkgPath string
+	name    string
+}
+
+// namedTypes is types and types of their inject fields by name, only named types and pointers to them are recorded.
+type namedTypes map[typeName]reflect.Type
+
+func (s namedTypes) add(tp reflect.Type) {
+	base := tp
+	if base.Kind() == reflect.Ptr {
+		base = base.Elem()
+	}
+	if base.Name() == "" || base.PkgPath() == "" {
+		return
+	}
+	name := typeName{pkgPath: base.PkgPath(), name: base.Name()}
+	if _, ok := s[name]; ok {
+		return
+	}
+	s[name] = base
+	if base.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < base.NumField(); i++ {
+		if _, ok := base.Field(i).Tag.Lookup(injectTag); ok {
+			s.add(base.Field(i).Type)
+		}
+	}
+}
+
+func (s namedTypes) addModule(m *Module, visited map[*Module]bool) {
+	if m == nil || visited[m] {
+		return
+	}
+	visited[m] = true
+	for _, sub := range m.Modules {
+		s.addModule(sub, visited)
+	}
+	for _, objects := range [][]interface{}{m.Objects, m.PrivateObjects} {
+		for _, o := range objects {
+			s.addBinding(o)
+		}
+	}
+	for _, named := range []map[string]interface{}{m.Named, m.PrivateNamed} {
+		for _, o := range named {
+			s.addBinding(o)
+		}
+	}
+	for _, funcs := range [][]InjectFunc{m.Funcs, m.PrivateFuncs} {
+		for _, ifn := range funcs {
+			s.addBinding(ifn)
+		}
+	}
+}
+
+func (s namedTypes) addBinding(o interface{}) {
+	if ifn, ok := o.(InjectFunc); ok {
+		if ifn.Fn != nil && reflect.TypeOf(ifn.Fn).Kind() == reflect.Func && reflect.TypeOf(ifn.Fn).NumOut() > 0 {
+			s.add(reflect.TypeOf(ifn.Fn).Out(0))
+		}
+		return
+	}
+	if o != nil {
+		s.add(reflect.TypeOf(o))
+	}
+}
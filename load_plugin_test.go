@@ -0,0 +1,74 @@
+package injectgo
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type pluginClient struct {
+	Addr string
+}
+
+type pluginService struct {
+	Client *pluginClient `inject:""`
+}
+
+func withPluginLoader(l PluginLoader) func() {
+	pluginMu.Lock()
+	prev := pluginLoader
+	pluginLoader = l
+	pluginMu.Unlock()
+	return func() {
+		RegisterPluginLoader(prev)
+	}
+}
+
+func TestContainer_LoadPlugin(t *testing.T) {
+	defer withPluginLoader(nil)()
+	c := NewContainer()
+	err := c.LoadPlugin("a.so")
+	assert.EqualError(t, err, "load plugin a.so error: no loader registered, import github.com/RivenZoo/injectgo/plugin")
+
+	modules := map[string]*Module{
+		"client.so": {Name: "client", Objects: []interface{}{&pluginClient{Addr: "plugin"}}},
+		"dup.so":    {Name: "client"},
+	}
+	RegisterPluginLoader(func(path string) (*Module, error) {
+		if m, ok := modules[path]; ok {
+			return m, nil
+		}
+		return nil, errors.New("not found")
+	})
+
+	svc := &pluginService{}
+	c.Provide(svc)
+	assert.NoError(t, c.LoadPlugin("client.so"))
+	assert.Equal(t, []string{"client"}, c.Modules())
+	assert.EqualError(t, c.LoadPlugin("missing.so"), "load plugin missing.so error: not found")
+	assert.EqualError(t, c.LoadPlugin("dup.so"),
+		"load plugin dup.so error: install module client error: duplicate module name: client")
+
+	c.Populate(nil)
+	assert.Equal(t, "plugin", svc.Client.Addr)
+	assert.Equal(t, "client.so", c.Graph().Nodes[1].Site)
+	assert.True(t, errors.Is(c.LoadPlugin("client.so"), ErrFrozen))
+}
+
+func TestContainer_LoadPluginIncompatible(t *testing.T) {
+	// pluginClient declared again stands for the type of plugin built with other version of the package
+	type pluginClient struct {
+		Addr string
+	}
+	defer withPluginLoader(func(path string) (*Module, error) {
+		return &Module{Name: "client", Funcs: []InjectFunc{{Fn: func() *pluginClient { return &pluginClient{} }}}}, nil
+	})()
+
+	c := NewContainer()
+	c.Provide(&pluginService{})
+	err := c.LoadPlugin("client.so")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "load plugin client.so error: incompatible types:\ntype injectgo.pluginClient is not the type of the program")
+	assert.Empty(t, c.Modules())
+}
@@ -0,0 +1,34 @@
+package injectgo
+
+// Lookup return populated object provided by name.
+// It returns false if c is not populated, is populating or nothing is provided by name.
+func (c *Container) Lookup(name string) (interface{}, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if !c.populated || c.state == StatePopulating {
+		return nil, false
+	}
+	o := c.graph.findNamedObject(name)
+	if o == nil {
+		return nil, false
+	}
+	return o.value.Interface(), true
+}
+
+// LookupType return populated unnamed object which would be injected into field of the type,
+// (*Iface)(nil) refers to Iface and other values refer to their own type like (*Config)(nil) refers to *Config.
+// It returns false if c is not populated, is populating or no object matches the type.
+func (c *Container) LookupType(ifaceOrType interface{}) (interface{}, bool) {
+	tp := bindingType(ifaceOrType)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if !c.populated || c.state == StatePopulating {
+		return nil, false
+	}
+	o := c.graph.findUnnamedObjectByType(tp)
+	if o == nil {
+		return nil, false
+	}
+	return o.value.Interface(), true
+}
@@ -0,0 +1,181 @@
+package injectgo
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type lookupClient struct {
+	Addr string
+}
+
+type lookupService struct {
+	Client   *lookupClient `inject:""`
+	Stringer fmt.Stringer  `inject:"person"`
+}
+
+func TestLookup(t *testing.T) {
+	c := NewContainer()
+	svc := &lookupService{}
+	cli := &lookupClient{}
+	c.Provide(svc, cli)
+	c.ProvideByName("person", &Person{Name: "p"})
+
+	_, ok := c.Lookup("person")
+	assert.False(t, ok, "should not found before populate")
+
+	c.Populate(nil)
+	o, ok := c.Lookup("person")
+	assert.True(t, ok)
+	assert.Equal(t, "name:p", o.(fmt.Stringer).String())
+	_, ok = c.Lookup("unknown")
+	assert.False(t, ok)
+
+	o, ok = c.LookupType((*lookupClient)(nil))
+	assert.True(t, ok)
+	assert.True(t, o == cli)
+	o, ok = c.LookupType((*fmt.Stringer)(nil))
+	assert.False(t, ok, "named object should not be looked up by type")
+	assert.Nil(t, o)
+}
+
+func TestConcurrentProvideAndLookup(t *testing.T) {
+	c := NewContainer()
+	svc := &lookupService{}
+	c.Provide(svc)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			switch i {
+			case 0:
+				c.Provide(&lookupClient{Addr: "addr"})
+			case 1:
+				c.ProvideByName("person", &Person{Name: "p"})
+			default:
+				c.ProvideByName(fmt.Sprintf("client%d", i), &lookupClient{})
+				c.ProvideFuncByName(fmt.Sprintf("func%d", i), InjectFunc{
+					Fn: func() *lookupClient { return &lookupClient{} },
+				})
+			}
+			c.Graph()
+		}(i)
+	}
+	wg.Wait()
+	c.Populate(nil)
+	assert.Equal(t, "addr", svc.Client.Addr)
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			o, ok := c.Lookup(fmt.Sprintf("func%d", i+2))
+			if i < 8 {
+				assert.True(t, ok)
+				assert.IsType(t, &lookupClient{}, o)
+			}
+			_, ok = c.LookupType((*lookupClient)(nil))
+			assert.True(t, ok)
+			c.Manifest()
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestProvideDuringPopulate(t *testing.T) {
+	c := NewContainer()
+	c.Provide(&lookupService{}, &lookupClient{})
+	c.ProvideFuncByName("person", InjectFunc{
+		Fn: func() fmt.Stringer {
+			c.Provide(&Person{})
+			return &Person{}
+		},
+	})
+
+	r := func() (r interface{}) {
+		defer func() {
+			r = recover()
+		}()
+		c.Populate(nil)
+		return nil
+	}()
+	assert.NotNil(t, r)
+	assert.True(t, strings.Contains(fmt.Sprint(r), "container is populating"), "%v", r)
+
+	assert.NotPanics(t, func() {
+		c.Provide(&Person{})
+	}, "should be able to provide after populate failed")
+}
+
+func TestConcurrentPopulate(t *testing.T) {
+	c := NewContainer()
+	started := make(chan struct{})
+	done := make(chan struct{})
+	c.ProvideFunc(InjectFunc{
+		Fn: func() *lookupClient {
+			close(started)
+			<-done
+			return &lookupClient{}
+		},
+	})
+
+	populated := make(chan struct{})
+	go func() {
+		defer close(populated)
+		c.Populate(nil)
+	}()
+	<-started
+	assert.Panics(t, func() {
+		c.Populate(nil)
+	}, "should panic because container is populating")
+	assert.Panics(t, func() {
+		c.ProvideByName("person", &Person{})
+	}, "should panic because container is populating")
+	assert.Error(t, c.Replace("person", &Person{}))
+	_, ok := c.LookupType((*lookupClient)(nil))
+	assert.False(t, ok)
+	close(done)
+
+	<-populated
+	_, ok = c.LookupType((*lookupClient)(nil))
+	assert.True(t, ok)
+}
+
+// run with -race to check lookups are safe during incremental Populate
+func TestLookupDuringPopulate(t *testing.T) {
+	c := NewContainer()
+	cli := &lookupClient{}
+	c.Provide(cli)
+	c.Populate(nil)
+
+	done := make(chan struct{})
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			if o, ok := c.LookupType((*lookupClient)(nil)); ok {
+				assert.True(t, o == cli)
+			}
+			c.Lookup("person0")
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		c.Unfreeze()
+		c.ProvideByName(fmt.Sprintf("person%d", i), &Person{})
+		c.Populate(nil)
+	}
+	close(done)
+	wg.Wait()
+}
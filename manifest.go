@@ -0,0 +1,96 @@
+package injectgo
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// describeBinding return type with name and label.
+func describeBinding(tp, name, label string) []string {
+	s := []string{tp}
+	if name != "" {
+		s = append(s, fmt.Sprintf("name=%s", name))
+	}
+	if label != "" {
+		s = append(s, fmt.Sprintf("label=%s", label))
+	}
+	return s
+}
+
+// describeNode return node type with name, label and kind, without id or site,
+// so it is stable between runs.
+func describeNode(n Node) string {
+	s := describeBinding(n.Type, n.Name, n.Label)
+	if n.Module != "" {
+		s = append(s, fmt.Sprintf("module=%s", n.Module))
+	}
+	if n.Private {
+		s = append(s, "[private]")
+	}
+	if n.Kind != NodeObject {
+		s = append(s, fmt.Sprintf("[%s]", n.Kind))
+	}
+	if n.Inner {
+		s = append(s, "[inner]")
+	}
+	return strings.Join(s, " ")
+}
+
+// Manifest return a stable sorted text of the populated container,
+// includes every object, each inject field and the object satisfied it, selected and skipped functions.
+// It is readable in diff and suitable for golden file tests.
+// It panics if c is not populated.
+func (c *Container) Manifest() string {
+	c.rlockPopulated()
+	defer c.mu.RUnlock()
+	if !c.populated {
+		panic(fmt.Errorf("manifest of unpopulated container"))
+	}
+	g := c.currentGraph()
+
+	objects := make([]string, 0, len(g.Nodes))
+	for _, n := range g.Nodes {
+		objects = append(objects, describeNode(n))
+	}
+	sort.Strings(objects)
+
+	fields := make([]string, 0, len(g.Edges))
+	for _, e := range g.Edges {
+		from, _ := g.Node(e.From)
+		to := "<unresolved>"
+		if n, ok := g.Node(e.To); ok {
+			to = describeNode(n)
+		}
+		fields = append(fields, fmt.Sprintf("%s.%s `inject:\"%s\"` <- %s", describeNode(from), e.Field, e.Tag, to))
+	}
+	sort.Strings(fields)
+
+	functions := make([]string, 0, len(c.functionResults))
+	for _, r := range c.functionResults {
+		desc := strings.Join(describeBinding(r.tp.String(), r.name, r.label), " ")
+		if r.selected {
+			functions = append(functions, fmt.Sprintf("selected %s", desc))
+		} else {
+			functions = append(functions, fmt.Sprintf("skipped %s (%s)", desc, r.reason))
+		}
+	}
+	sort.Strings(functions)
+
+	sections := []struct {
+		title string
+		lines []string
+	}{
+		{"objects", objects},
+		{"fields", fields},
+		{"functions", functions},
+	}
+	b := &strings.Builder{}
+	for _, sec := range sections {
+		fmt.Fprintf(b, "%s:\n", sec.title)
+		for _, l := range sec.lines {
+			fmt.Fprintf(b, "\t%s\n", l)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,41 @@
+package injectgo
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainer_Manifest(t *testing.T) {
+	c := NewContainer()
+	assert.Panics(t, func() {
+		_ = c.Manifest()
+	}, "should panic because container is not populated")
+
+	provideGraphWiring(c)
+	c.ProvideFunc(InjectFunc{
+		Fn:    func() fmt.Stringer { return &Person{} },
+		Label: "dev",
+	}, InjectFunc{
+		Fn:         func() *graphClient { return &graphClient{} },
+		Conditions: []Condition{OnMissingType((*graphClient)(nil))},
+	})
+	c.Populate(labelSelector{labels: []string{"prod"}})
+
+	expected := `objects:
+	*injectgo.Person label=prod
+	*injectgo.graphClient
+	*injectgo.graphClient name=named [function]
+	*injectgo.graphService
+fields:
+	*injectgo.graphService.Client ` + "`inject:\"\"`" + ` <- *injectgo.graphClient
+	*injectgo.graphService.Named ` + "`inject:\"named\"`" + ` <- *injectgo.graphClient name=named [function]
+	*injectgo.graphService.Stringer ` + "`inject:\"\"`" + ` <- *injectgo.Person label=prod
+functions:
+	selected *injectgo.graphClient name=named
+	skipped *injectgo.graphClient (conditions not match)
+	skipped fmt.Stringer label=dev (label not selected)
+`
+	assert.Equal(t, expected, c.Manifest())
+}
@@ -0,0 +1,259 @@
+package injectgo
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// Module groups objects and functions provided together, eg. by a shared library.
+// Objects, Named and Funcs are the public surface of module.
+// Private bindings are only injected into objects and objects of functions installed by the same module,
+// Populate panics with VisibilityError if they are injected into others.
+type Module struct {
+	Name    string                 // unique in container, recorded in error messages and graph
+	Label   string                 // default label of objects, functions and nested modules without label
+	Objects []interface{}          // provided like Provide
+	Named   map[string]interface{} // provided by name, value is an object or InjectFunc
+	Funcs   []InjectFunc           // provided like ProvideFunc
+	Modules []*Module              // nested modules, installed before objects and functions of this module
+
+	PrivateObjects []interface{}          // like Objects but private
+	PrivateNamed   map[string]interface{} // like Named but private
+	PrivateFuncs   []InjectFunc           // like Funcs but private
+}
+
+// Install provide objects and functions of modules and their nested modules.
+// A module already installed is skipped, so modules can share nested modules.
+// It panics like Provide, or if module has no name or another module with the same name is installed.
+// If it panics, modules installed by it and their bindings are removed, so Install can be retried.
+func (c *Container) Install(modules ...*Module) {
+	c.installModules(callerSite(1), modules...)
+}
+
+func (c *Container) installModules(site string, modules ...*Module) {
+	installed := make(map[string]bool)
+	defer func() {
+		if r := recover(); r != nil {
+			c.uninstall(installed)
+			panic(r)
+		}
+	}()
+	for _, m := range modules {
+		c.install(site, m, "", installed)
+	}
+}
+
+// install provide bindings of m and its nested modules, names of modules recorded are added to installed.
+func (c *Container) install(site string, m *Module, label string, installed map[string]bool) {
+	if m.Name == "" {
+		panic(fmt.Errorf("install module error: module should have name"))
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			if err, ok := r.(error); ok {
+				panic(fmt.Errorf("install module %s error: %w", m.Name, err))
+			}
+			panic(fmt.Errorf("install module %s error: %v", m.Name, r))
+		}
+	}()
+	c.lockRegistering()
+	prev, ok := c.modules[m.Name]
+	if !ok {
+		c.modules[m.Name] = m
+		installed[m.Name] = true
+	}
+	c.mu.Unlock()
+	if ok {
+		if prev != m {
+			panic(fmt.Errorf("duplicate module name: %s", m.Name))
+		}
+		return
+	}
+
+	if m.Label != "" {
+		label = m.Label
+	}
+	for _, sub := range m.Modules {
+		c.install(site, sub, label, installed)
+	}
+	c.provideBindings(source{site: site, module: m.Name}, label, m.Objects, m.Named, m.Funcs)
+	c.provideBindings(source{site: site, module: m.Name, private: true}, label, m.PrivateObjects, m.PrivateNamed, m.PrivateFuncs)
+}
+
+// uninstall remove modules and bindings provided by them.
+func (c *Container) uninstall(modules map[string]bool) {
+	if len(modules) == 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for name := range modules {
+		delete(c.modules, name)
+	}
+	values := c.unnamedValues[:0]
+	for _, pv := range c.unnamedValues {
+		if !modules[pv.module] {
+			values = append(values, pv)
+		}
+	}
+	c.unnamedValues = values
+	for name, pv := range c.namedValues {
+		if modules[pv.module] {
+			delete(c.namedValues, name)
+		}
+	}
+	funcs := c.unnamedFunctions[:0]
+	for _, ifn := range c.unnamedFunctions {
+		if !modules[ifn.module] {
+			funcs = append(funcs, ifn)
+		}
+	}
+	c.unnamedFunctions = funcs
+	names := c.namedFuncOrder[:0]
+	for _, name := range c.namedFuncOrder {
+		if modules[c.namedFunctions[name].module] {
+			delete(c.namedFunctions, name)
+			continue
+		}
+		names = append(names, name)
+	}
+	c.namedFuncOrder = names
+}
+
+func (c *Container) provideBindings(src source, label string, objs []interface{}, named map[string]interface{}, funcs []InjectFunc) {
+	c.provideValues(src, label, objs...)
+	names := make([]string, 0, len(named))
+	for name := range named {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if ifn, ok := named[name].(InjectFunc); ok {
+			c.provideNamedFunc(src, name, withDefaultLabel(ifn, label))
+			continue
+		}
+		c.provideNamedValue(src, name, label, named[name])
+	}
+	labeled := make([]InjectFunc, 0, len(funcs))
+	for _, ifn := range funcs {
+		labeled = append(labeled, withDefaultLabel(ifn, label))
+	}
+	c.provideFuncs(src, labeled...)
+}
+
+func withDefaultLabel(ifn InjectFunc, label string) InjectFunc {
+	if ifn.Label == "" {
+		ifn.Label = label
+	}
+	return ifn
+}
+
+// Modules return names of installed modules in sorted order.
+func (c *Container) Modules() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	names := make([]string, 0, len(c.modules))
+	for name := range c.modules {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Violation is an inject field resolved to a private binding of another module.
+type Violation struct {
+	Object        string // type of object has the field
+	ObjectModule  string // empty if object is not installed by module
+	Field         string
+	Binding       string // type of private binding, with name if it is named
+	BindingModule string
+}
+
+func (v Violation) String() string {
+	objModule := "no module"
+	if v.ObjectModule != "" {
+		objModule = "module " + v.ObjectModule
+	}
+	return fmt.Sprintf("field %s of %s (%s) is private %s of module %s",
+		v.Field, v.Object, objModule, v.Binding, v.BindingModule)
+}
+
+// visibilityViolations return inject fields of objects selected in current Populate,
+// which are resolved by graph to private bindings of other modules, bindings of parents included.
+// It is called after selected objects are provided to graph.
+func (c *Container) visibilityViolations() []Violation {
+	bindings := make(map[objectKey]binding)
+	record := func(o selectedObject) {
+		if k, ok := keyOf(o.name, o.value); ok && !o.inner {
+			if _, dup := bindings[k]; !dup {
+				bindings[k] = o.binding
+			}
+		}
+	}
+	for _, o := range c.selectedObjects {
+		record(o)
+	}
+	for p := c.parent; p != nil; p = p.parent {
+		p.mu.RLock()
+		for _, o := range p.selectedObjects[:p.populatedObjects] {
+			record(o)
+		}
+		p.mu.RUnlock()
+	}
+
+	violations := make([]Violation, 0)
+	check := func(tp reflect.Type, owner binding) {
+		for _, f := range graphFields(tp) {
+			tag := f.Tag.Get(injectTag)
+			if tag == privateTag {
+				continue
+			}
+			field := &injectField{fieldType: f.Type, tagName: tag}
+			var to binding
+			var toType reflect.Type
+			if o := c.graph.resolveExistingObject(field); o != nil {
+				k, _ := keyOf(tag, o.value)
+				to, toType = bindings[k], o.value.Type()
+			} else if t := c.graph.findTransient(field); t != nil {
+				to = binding{name: t.name, module: t.ifn.module, private: t.ifn.private}
+				toType = t.ifn.returnType()
+			}
+			if !to.private || to.module == owner.module {
+				continue
+			}
+			bindingText := toType.String()
+			if to.name != "" {
+				bindingText = fmt.Sprintf("%s %s", toType, to.name)
+			}
+			violations = append(violations, Violation{Object: tp.String(), ObjectModule: owner.module, Field: f.Name,
+				Binding: bindingText, BindingModule: to.module})
+		}
+	}
+	for _, o := range c.selectedObjects[c.populatedObjects:] {
+		if !o.inner {
+			check(o.value.Type(), o.binding)
+		}
+	}
+	for _, t := range c.selectedTransients {
+		check(t.ifn.returnType(), binding{module: t.ifn.module})
+	}
+	return violations
+}
+
+// objectKey identifies an object provided by name or unnamed.
+type objectKey struct {
+	name string
+	ptr  uintptr
+}
+
+// keyOf return key of pointer object v, or object v of interface holds.
+func keyOf(name string, v reflect.Value) (objectKey, bool) {
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Ptr {
+		return objectKey{}, false
+	}
+	return objectKey{name: name, ptr: v.Pointer()}, true
+}
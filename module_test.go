@@ -0,0 +1,209 @@
+package injectgo
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type moduleClient struct {
+	Addr string
+}
+
+type moduleCache struct {
+	Client *moduleClient `inject:""`
+}
+
+type moduleService struct {
+	Cache  *moduleCache  `inject:"cache"`
+	Client *moduleClient `inject:""`
+}
+
+type moduleSelector map[string]bool
+
+func (s moduleSelector) IsLabelAllowed(label string) bool {
+	return s[label]
+}
+
+func TestInstall(t *testing.T) {
+	client := &moduleClient{Addr: "127.0.0.1"}
+	base := &Module{Name: "base", Objects: []interface{}{client}}
+	cache := &Module{
+		Name:    "cache",
+		Label:   "prod",
+		Named:   map[string]interface{}{"cache": InjectFunc{Fn: func() *moduleCache { return &moduleCache{} }}},
+		Modules: []*Module{base},
+	}
+	devCache := &Module{
+		Name:  "devcache",
+		Label: "dev",
+		Named: map[string]interface{}{"devcache": &moduleCache{}},
+	}
+	s := &moduleService{}
+
+	c := NewContainer()
+	c.Provide(s)
+	c.Install(base, cache, devCache)
+	assert.Equal(t, []string{"base", "cache", "devcache"}, c.Modules())
+	c.Populate(moduleSelector{"prod": true})
+
+	assert.Equal(t, client, s.Client)
+	assert.Equal(t, client, s.Cache.Client)
+	_, ok := c.Lookup("devcache")
+	assert.False(t, ok, "module label should be default label of its objects")
+
+	modules := make(map[string]string)
+	for _, n := range c.Graph().Nodes {
+		modules[n.Type+n.Name] = n.Module
+	}
+	assert.Equal(t, map[string]string{
+		"*injectgo.moduleService":    "",
+		"*injectgo.moduleClient":     "base",
+		"*injectgo.moduleCachecache": "cache",
+	}, modules)
+	assert.Contains(t, c.Manifest(), "*injectgo.moduleClient module=base")
+
+	assert.Panics(t, func() {
+		c := NewContainer()
+		c.Install(base, &Module{Name: "base"})
+	}, "should panic because module name is duplicate")
+	assert.Panics(t, func() {
+		c := NewContainer()
+		c.Install(&Module{})
+	}, "should panic because module has no name")
+}
+
+func TestInstall_Error(t *testing.T) {
+	c := NewContainer()
+	c.Freeze()
+	err := recoverError(func() {
+		c.Install(&Module{Name: "client"})
+	})
+	assert.True(t, errors.Is(err, ErrFrozen))
+
+	c = NewContainer()
+	err = recoverError(func() {
+		c.Install(&Module{Name: "outer", Modules: []*Module{{Name: "inner", Objects: []interface{}{moduleClient{}}}}})
+	})
+	assert.Contains(t, err.Error(), "install module outer error: install module inner error: check obj")
+
+	/// test failed install is rolled back and can be retried
+	c = NewContainer()
+	client := &moduleClient{}
+	shared := &Module{Name: "shared", Objects: []interface{}{client}}
+	c.Install(shared)
+	broken := &Module{
+		Name:    "broken",
+		Named:   map[string]interface{}{"cache": &moduleCache{}},
+		Funcs:   []InjectFunc{{Fn: func() *moduleCache { return &moduleCache{} }}},
+		Modules: []*Module{shared, {Name: "inner", Named: map[string]interface{}{"inner": client}}},
+		Objects: []interface{}{moduleClient{}},
+	}
+	assert.Panics(t, func() {
+		c.Install(broken)
+	})
+	assert.Equal(t, []string{"shared"}, c.Modules())
+	broken.Objects = nil
+	c.Install(broken)
+	assert.Equal(t, []string{"broken", "inner", "shared"}, c.Modules())
+	c.Populate(nil)
+	cache, ok := c.Lookup("cache")
+	assert.True(t, ok)
+	assert.Equal(t, client, cache.(*moduleCache).Client)
+	assert.Len(t, c.Graph().Nodes, 4)
+
+	c = NewContainer()
+	c.Install(&Module{Name: "client", Funcs: []InjectFunc{{Fn: func() (*moduleClient, error) {
+		return nil, fmt.Errorf("dial failed")
+	}}}})
+	err = recoverError(func() {
+		c.Populate(nil)
+	})
+	assert.EqualError(t, err, "unamed function of module client error: dial failed")
+}
+
+func recoverError(fn func()) (err error) {
+	defer func() {
+		err, _ = recover().(error)
+	}()
+	fn()
+	return nil
+}
+
+func TestInstall_Private(t *testing.T) {
+	newCacheModule := func() *Module {
+		return &Module{
+			Name:           "cache",
+			Named:          map[string]interface{}{"cache": InjectFunc{Fn: func() *moduleCache { return &moduleCache{} }}},
+			PrivateObjects: []interface{}{&moduleClient{Addr: "cache"}},
+		}
+	}
+
+	c := NewContainer()
+	cache := &moduleCache{}
+	c.Install(newCacheModule())
+	c.ProvideByName("other", cache)
+	err := recoverError(func() {
+		c.Populate(nil)
+	})
+	assert.IsType(t, &VisibilityError{}, err)
+	assert.EqualError(t, err, "private binding injected into other module: "+
+		"field Client of *injectgo.moduleCache (no module) is private *injectgo.moduleClient of module cache")
+
+	c = NewContainer()
+	s := &moduleService{}
+	c.Install(newCacheModule(), &Module{Name: "service", Objects: []interface{}{s}})
+	err = recoverError(func() {
+		c.Populate(nil)
+	})
+	assert.EqualError(t, err, "private binding injected into other module: "+
+		"field Client of *injectgo.moduleService (module service) is private *injectgo.moduleClient of module cache")
+
+	c = NewContainer()
+	c.Install(newCacheModule())
+	c.Populate(nil)
+	obj, ok := c.Lookup("cache")
+	assert.True(t, ok)
+	assert.Equal(t, "cache", obj.(*moduleCache).Client.Addr)
+	private := 0
+	for _, n := range c.Graph().Nodes {
+		if n.Private {
+			private++
+			assert.Equal(t, "cache", n.Module)
+		}
+	}
+	assert.Equal(t, 1, private)
+}
+
+func TestInstall_PrivateResolved(t *testing.T) {
+	cacheModule := &Module{Name: "cache", PrivateObjects: []interface{}{&moduleClient{Addr: "cache"}}}
+
+	/// test object injected by graph is checked, the latest provided one of the same type
+	c := NewContainer()
+	c.Provide(&moduleClient{Addr: "public"}, &moduleCache{})
+	c.Install(cacheModule)
+	err := recoverError(func() {
+		c.Populate(nil)
+	})
+	assert.EqualError(t, err, "private binding injected into other module: "+
+		"field Client of *injectgo.moduleCache (no module) is private *injectgo.moduleClient of module cache")
+	c.Provide(&moduleClient{Addr: "public"})
+	c.Populate(nil)
+	obj, ok := c.LookupType((*moduleCache)(nil))
+	assert.True(t, ok)
+	assert.Equal(t, "public", obj.(*moduleCache).Client.Addr)
+
+	/// test private bindings of parent are checked in scope
+	c = NewContainer()
+	c.Install(cacheModule)
+	c.Populate(nil)
+	s := c.NewScope()
+	s.Provide(&moduleCache{})
+	err = recoverError(func() {
+		s.Populate(nil)
+	})
+	assert.EqualError(t, err, "private binding injected into other module: "+
+		"field Client of *injectgo.moduleCache (no module) is private *injectgo.moduleClient of module cache")
+}
@@ -0,0 +1,39 @@
+//go:build plugintest
+
+package plugin
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/RivenZoo/injectgo"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestLoadPlugin_Build builds testdata/greeter as a real plugin and loads it, run by
+//
+//	go test -tags plugintest ./plugin
+func TestLoadPlugin_Build(t *testing.T) {
+	so := filepath.Join(t.TempDir(), "greeter.so")
+	out, err := exec.Command("go", "build", "-buildmode=plugin", "-o", so, "./testdata/greeter").CombinedOutput()
+	if !assert.NoError(t, err, string(out)) {
+		return
+	}
+
+	type app struct {
+		Greeter fmt.Stringer `inject:"greeter"`
+	}
+	a := &app{}
+	c := injectgo.NewContainer()
+	c.Provide(a)
+	assert.NoError(t, c.LoadPlugin(so))
+	assert.Equal(t, []string{"greeter"}, c.Modules())
+	c.Populate(nil)
+	assert.Equal(t, "hello plugin", a.Greeter.String())
+
+	err = c.LoadPlugin(so)
+	assert.Error(t, err, "should fail because container is frozen")
+	assert.Contains(t, err.Error(), "load plugin "+so+" error")
+}
@@ -0,0 +1,60 @@
+// Package plugin registers loader of Go plugins used by injectgo Container.LoadPlugin.
+// It is separated from injectgo, so programs not loading plugins do not link package plugin and cgo,
+// import it for effect to enable LoadPlugin:
+//
+//	import _ "github.com/RivenZoo/injectgo/plugin"
+package plugin
+
+import (
+	"fmt"
+	goplugin "plugin"
+
+	"github.com/RivenZoo/injectgo"
+)
+
+// Symbol is the symbol looked up by Open.
+const Symbol = "InjectModule"
+
+func init() {
+	injectgo.RegisterPluginLoader(Open)
+}
+
+// Open open Go plugin at path and return module exported by its symbol InjectModule, declared as one of
+//
+//	var InjectModule injectgo.Module
+//	var InjectModule *injectgo.Module
+//	func InjectModule() *injectgo.Module
+//
+// Plugin should be built with the same version of injectgo and other packages shared with the program.
+// It returns error if plugin can not be opened, symbol is missing or has other type.
+func Open(path string) (*injectgo.Module, error) {
+	p, err := goplugin.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	sym, err := p.Lookup(Symbol)
+	if err != nil {
+		return nil, err
+	}
+	return module(sym)
+}
+
+// module return module of plugin symbol sym.
+func module(sym goplugin.Symbol) (*injectgo.Module, error) {
+	var m *injectgo.Module
+	switch s := sym.(type) {
+	case *injectgo.Module:
+		m = s
+	case **injectgo.Module:
+		m = *s
+	case func() *injectgo.Module:
+		m = s()
+	default:
+		return nil, fmt.Errorf("symbol %s has type %T, should be injectgo.Module, *injectgo.Module or func() *injectgo.Module "+
+			"of the same injectgo version", Symbol, sym)
+	}
+	if m == nil {
+		return nil, fmt.Errorf("symbol %s is nil module", Symbol)
+	}
+	return m, nil
+}
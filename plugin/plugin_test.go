@@ -0,0 +1,46 @@
+package plugin
+
+import (
+	"testing"
+
+	"github.com/RivenZoo/injectgo"
+	"github.com/stretchr/testify/assert"
+)
+
+type client struct {
+	Addr string
+}
+
+func TestLoadPlugin(t *testing.T) {
+	c := injectgo.NewContainer()
+	err := c.LoadPlugin("testdata/missing.so")
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "load plugin testdata/missing.so error")
+	assert.NotContains(t, err.Error(), "no loader registered")
+}
+
+func TestModule(t *testing.T) {
+	newModule := func(name string) *injectgo.Module {
+		return &injectgo.Module{Name: name, Objects: []interface{}{&client{Addr: name}}}
+	}
+	mod := *newModule("var")
+	ptr := newModule("ptr")
+	fn := func() *injectgo.Module { return newModule("func") }
+
+	m, err := module(&mod)
+	assert.NoError(t, err)
+	assert.Equal(t, "var", m.Name)
+	m, err = module(&ptr)
+	assert.NoError(t, err)
+	assert.True(t, m == ptr)
+	m, err = module(fn)
+	assert.NoError(t, err)
+	assert.Equal(t, "func", m.Name)
+
+	var nilModule *injectgo.Module
+	_, err = module(&nilModule)
+	assert.EqualError(t, err, "symbol InjectModule is nil module")
+	_, err = module(func() {})
+	assert.EqualError(t, err, "symbol InjectModule has type func(), "+
+		"should be injectgo.Module, *injectgo.Module or func() *injectgo.Module of the same injectgo version")
+}
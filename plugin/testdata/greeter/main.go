@@ -0,0 +1,26 @@
+// Command greeter is a plugin exporting injectgo module, built by plugintest tests.
+package main
+
+import (
+	"github.com/RivenZoo/injectgo"
+)
+
+type greeter struct {
+	Name string
+}
+
+func (g *greeter) String() string {
+	return "hello " + g.Name
+}
+
+func newGreeter() *greeter {
+	return &greeter{Name: "plugin"}
+}
+
+// InjectModule is looked up by package injectgo/plugin.
+var InjectModule = &injectgo.Module{
+	Name:  "greeter",
+	Named: map[string]interface{}{"greeter": injectgo.InjectFunc{Fn: newGreeter}},
+}
+
+func main() {}
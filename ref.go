@@ -0,0 +1,262 @@
+package injectgo
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultGracePeriod is the duration an object swapped out of Ref is kept before closed.
+const DefaultGracePeriod = 10 * time.Second
+
+// Ref is a swappable reference to an object of type T.
+// Provide it by name and inject it into fields of type *Ref[T], eg. `inject:"client"`,
+// then Container.Swap replace its object at runtime.
+// The object T is populated, initialized and closed like an object provided to container,
+// but never injected into other objects directly.
+type Ref[T any] struct {
+	v     atomic.Pointer[T]
+	mu    sync.Mutex
+	grace time.Duration
+
+	swapHooks       []func(old, new T)
+	closeErrorHooks []func(error)
+}
+
+// NewRef return a Ref of obj, obj should be pointer to struct or interface.
+func NewRef[T any](obj T) *Ref[T] {
+	r := &Ref[T]{grace: DefaultGracePeriod}
+	r.v.Store(&obj)
+	return r
+}
+
+// Get return current object, it is safe for concurrent use.
+func (r *Ref[T]) Get() T {
+	return *r.v.Load()
+}
+
+// SetGracePeriod set duration an object swapped out is kept before closed, return r.
+func (r *Ref[T]) SetGracePeriod(d time.Duration) *Ref[T] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.grace = d
+	return r
+}
+
+// OnSwap add fn called after object is swapped, in added order.
+func (r *Ref[T]) OnSwap(fn func(old, new T)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.swapHooks = append(r.swapHooks, fn)
+}
+
+// OnCloseError add fn called if Close of object swapped out returns error.
+func (r *Ref[T]) OnCloseError(fn func(error)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closeErrorHooks = append(r.closeErrorHooks, fn)
+}
+
+func (r *Ref[T]) elemType() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+func (r *Ref[T]) current() reflect.Value {
+	return concreteValue(reflect.ValueOf(r.v.Load()).Elem())
+}
+
+func (r *Ref[T]) swap(v reflect.Value) reflect.Value {
+	obj := v.Interface().(T)
+	return concreteValue(reflect.ValueOf(r.v.Swap(&obj)).Elem())
+}
+
+func (r *Ref[T]) swapped(old, new reflect.Value) {
+	r.mu.Lock()
+	hooks := append([]func(old, new T){}, r.swapHooks...)
+	r.mu.Unlock()
+	for _, fn := range hooks {
+		fn(old.Interface().(T), new.Interface().(T))
+	}
+}
+
+func (r *Ref[T]) gracePeriod() time.Duration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.grace
+}
+
+func (r *Ref[T]) closeError(err error) {
+	r.mu.Lock()
+	hooks := append([]func(error){}, r.closeErrorHooks...)
+	r.mu.Unlock()
+	for _, fn := range hooks {
+		fn(err)
+	}
+}
+
+// swappable is implemented by Ref of any type.
+type swappable interface {
+	elemType() reflect.Type
+	current() reflect.Value
+	swap(v reflect.Value) (old reflect.Value)
+	swapped(old, new reflect.Value)
+	gracePeriod() time.Duration
+	closeError(err error)
+}
+
+// selectRefValue select current object of v as inner object if v is Ref.
+func (c *Container) selectRefValue(v reflect.Value, b binding) {
+	ref, ok := v.Interface().(swappable)
+	if !ok {
+		return
+	}
+	cur := ref.current()
+	if !cur.IsValid() || !c.isStructPtrOrInterface(cur) {
+		panic(fmt.Errorf("check ref %s(%v) object: %v error: %v", b.name, v.Type(), cur, errValueNotPtrOrInterface))
+	}
+	c.selectInnerValue(cur, b, -1)
+}
+
+// Swap replace object of Ref provided by name with obj at runtime.
+// Inject fields of obj are fulfilled by populated objects, and Init is called if implemented,
+// then obj is swapped in and hooks added by Ref.OnSwap are called.
+// Object swapped out is closed after grace period of Ref if Closable is implemented,
+// or by Container.Close if it is earlier, obj is closed by Container.Close instead of it.
+// It returns error if c is not populated or closed, nothing provided by name is Ref,
+// obj is not assignable to object of Ref, inject fields can not be fulfilled or Init fails.
+func (c *Container) Swap(name string, obj interface{}) error {
+	c.swapMu.Lock()
+	defer c.swapMu.Unlock()
+
+	v := reflect.ValueOf(obj)
+	ref, idx, err := c.prepareSwap(name, v)
+	if err != nil {
+		return err
+	}
+	if i, ok := obj.(Initializable); ok {
+		if err := i.Init(); err != nil {
+			return fmt.Errorf("swap name %s init error: %v", name, err)
+		}
+	}
+
+	c.mu.Lock()
+	// c may be closed after prepareSwap, obj is never swapped in then
+	if c.state == StateClosing || c.state == StateClosed {
+		c.mu.Unlock()
+		if closer, ok := obj.(Closable); ok {
+			if err := closer.Close(); err != nil {
+				ref.closeError(err)
+			}
+		}
+		return fmt.Errorf("swap name %s error: %v", name, errClosed)
+	}
+	old := ref.swap(v)
+	c.graph.replaceObjectCall(old, v)
+	if idx >= 0 {
+		c.selectedObjects[idx].value = v
+	}
+	if closer, ok := old.Interface().(Closable); ok {
+		c.closeAfterGrace(ref, closer)
+	}
+	c.mu.Unlock()
+	ref.swapped(old, v)
+	return nil
+}
+
+// graceClose is close of object swapped out waiting for grace period of its Ref.
+type graceClose struct {
+	ref    swappable
+	closer Closable
+}
+
+func (g *graceClose) close() {
+	if err := g.closer.Close(); err != nil {
+		g.ref.closeError(err)
+	}
+}
+
+// closeAfterGrace close closer after grace period of ref, or by Container.Close if it is earlier.
+// c.mu should be locked.
+func (c *Container) closeAfterGrace(ref swappable, closer Closable) {
+	if c.graceCloses == nil {
+		c.graceCloses = make(map[*graceClose]bool)
+	}
+	g := &graceClose{ref: ref, closer: closer}
+	c.graceCloses[g] = true
+	c.graceWG.Add(1)
+	time.AfterFunc(ref.gracePeriod(), func() {
+		if c.takeGraceClose(g) {
+			defer c.graceWG.Done()
+			g.close()
+		}
+	})
+}
+
+// takeGraceClose remove g from pending closes, return false if it is already taken by Container.Close.
+func (c *Container) takeGraceClose(g *graceClose) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.graceCloses[g] {
+		return false
+	}
+	delete(c.graceCloses, g)
+	return true
+}
+
+// flushGraceCloses close objects swapped out without waiting for grace period,
+// and wait for those being closed after grace period.
+func (c *Container) flushGraceCloses() {
+	c.mu.Lock()
+	pending := make([]*graceClose, 0, len(c.graceCloses))
+	for g := range c.graceCloses {
+		pending = append(pending, g)
+	}
+	c.graceCloses = nil
+	c.mu.Unlock()
+
+	for _, g := range pending {
+		g.close()
+		c.graceWG.Done()
+	}
+	c.graceWG.Wait()
+}
+
+// prepareSwap find Ref provided by name and fulfill inject fields of v,
+// return Ref and index of its object in selectedObjects.
+func (c *Container) prepareSwap(name string, v reflect.Value) (swappable, int, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	switch {
+	case c.state == StateClosing || c.state == StateClosed:
+		return nil, -1, fmt.Errorf("swap name %s error: %v", name, errClosed)
+	case !c.populated || c.state == StatePopulating:
+		return nil, -1, fmt.Errorf("swap name %s error: %v", name, errNotPopulated)
+	}
+	o := c.graph.findNamedObject(name)
+	if o == nil {
+		return nil, -1, fmt.Errorf("swap name %s error: %v", name, errNotSwappable)
+	}
+	ref, ok := o.value.Interface().(swappable)
+	if !ok {
+		return nil, -1, fmt.Errorf("swap name %s error: %v", name, errNotSwappable)
+	}
+	if !v.IsValid() || !c.isStructPtrOrInterface(v) {
+		return nil, -1, fmt.Errorf("check obj: %v error: %v", v, errValueNotPtrOrInterface)
+	}
+	if !v.Type().AssignableTo(ref.elemType()) {
+		return nil, -1, fmt.Errorf("swap name %s error: %v not assignable to %v", name, v.Type(), ref.elemType())
+	}
+	if err := c.graph.fulfillObject(v); err != nil {
+		return nil, -1, fmt.Errorf("swap name %s error: %v", name, err)
+	}
+
+	cur := ref.current()
+	for i := range c.selectedObjects {
+		if c.selectedObjects[i].inner && isSameObject(c.selectedObjects[i].value, cur) {
+			return ref, i, nil
+		}
+	}
+	return ref, -1, nil
+}
@@ -0,0 +1,170 @@
+package injectgo
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type refConfig struct {
+	Addr string
+}
+
+type refClient struct {
+	Config *refConfig `inject:""`
+	Token  string
+
+	mu     sync.Mutex
+	inits  int
+	closed bool
+	err    error
+	onInit func()
+}
+
+func (c *refClient) Init() error {
+	c.inits++
+	if c.onInit != nil {
+		c.onInit()
+	}
+	return c.err
+}
+
+func (c *refClient) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	return nil
+}
+
+func (c *refClient) isClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+type refService struct {
+	Client *Ref[*refClient] `inject:"client"`
+}
+
+func TestRef_Swap(t *testing.T) {
+	c := NewContainer()
+	cfg := &refConfig{Addr: "addr"}
+	old := &refClient{Token: "old"}
+	svc := &refService{}
+	ref := NewRef(old).SetGracePeriod(10 * time.Millisecond)
+	c.Provide(svc, cfg)
+	c.ProvideByName("client", ref)
+
+	assert.Error(t, c.Swap("client", &refClient{}), "should fail because container is not populated")
+
+	c.Populate(nil)
+	assert.Equal(t, ref, svc.Client)
+	assert.Equal(t, cfg, svc.Client.Get().Config, "object of ref should be populated")
+	assert.Equal(t, 1, old.inits)
+
+	var swapped []string
+	ref.OnSwap(func(o, n *refClient) {
+		swapped = append(swapped, o.Token+"->"+n.Token)
+	})
+
+	failed := &refClient{Token: "failed", err: errors.New("init error")}
+	assert.Error(t, c.Swap("client", failed))
+	assert.Equal(t, "old", svc.Client.Get().Token)
+
+	assert.Error(t, c.Swap("unknown", &refClient{}))
+	assert.Error(t, c.Swap("client", &refConfig{}), "should fail because type not match")
+
+	cli := &refClient{Token: "new"}
+	assert.NoError(t, c.Swap("client", cli))
+	assert.Equal(t, "new", svc.Client.Get().Token)
+	assert.Equal(t, cfg, cli.Config)
+	assert.Equal(t, 1, cli.inits)
+	assert.Equal(t, []string{"old->new"}, swapped)
+	assert.False(t, old.isClosed(), "should be closed after grace period")
+
+	deadline := time.Now().Add(time.Second)
+	for !old.isClosed() && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	assert.True(t, old.isClosed())
+
+	c.Close()
+	assert.True(t, cli.isClosed())
+}
+
+func TestRef_SwapClose(t *testing.T) {
+	newContainer := func(old *refClient) *Container {
+		c := NewContainer()
+		c.Provide(&refService{}, &refConfig{})
+		c.ProvideByName("client", NewRef(old).SetGracePeriod(time.Hour))
+		c.Populate(nil)
+		return c
+	}
+
+	/// test objects waiting for grace period are closed by Close
+	old := &refClient{Token: "old"}
+	c := newContainer(old)
+	cli := &refClient{Token: "new"}
+	assert.NoError(t, c.Swap("client", cli))
+	assert.False(t, old.isClosed())
+	c.Close()
+	assert.True(t, old.isClosed(), "should be closed by Close before grace period")
+	assert.True(t, cli.isClosed())
+
+	/// test container closed after swap prepared
+	old = &refClient{Token: "old"}
+	c = newContainer(old)
+	cli = &refClient{Token: "new", onInit: func() {
+		c.Close()
+	}}
+	err := c.Swap("client", cli)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), errClosed.Error())
+	assert.True(t, cli.isClosed(), "object not swapped in should be closed")
+	assert.True(t, old.isClosed())
+	obj, _ := c.Lookup("client")
+	assert.Equal(t, "old", obj.(*Ref[*refClient]).Get().Token)
+}
+
+type refRepo interface {
+	Find() string
+}
+
+type refRepoImpl struct {
+	name string
+}
+
+func (r *refRepoImpl) Find() string {
+	return r.name
+}
+
+func TestRef_Interface(t *testing.T) {
+	c := NewContainer()
+	type user struct {
+		Repo *Ref[refRepo] `inject:"repo"`
+	}
+	u := &user{}
+	c.Provide(u)
+	c.ProvideByName("repo", NewRef[refRepo](&refRepoImpl{name: "a"}))
+	c.Populate(nil)
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			u.Repo.Get().Find()
+		}()
+	}
+	assert.NoError(t, c.Swap("repo", &refRepoImpl{name: "b"}))
+	wg.Wait()
+	assert.Equal(t, "b", u.Repo.Get().Find())
+
+	n, ok := c.Graph().Node("n2")
+	assert.True(t, ok)
+	assert.True(t, n.Inner)
+	assert.Equal(t, "*injectgo.refRepoImpl", n.Type)
+}
@@ -0,0 +1,102 @@
+package injectgo
+
+import (
+	"fmt"
+	"sync"
+)
+
+// registered is a function registered by Register or RegisterByName.
+type registered struct {
+	name string
+	ifn  InjectFunc
+}
+
+var (
+	registryLock sync.Mutex
+	registry     []registered
+)
+
+// Register registers funcs to the default registry, usually in init of the package provides them,
+// like database/sql drivers. They are provided to containers by ProvideRegistered.
+// It panics like ProvideFunc if any function is unsupported, or sets Receiver, which would be shared by containers.
+func Register(funcs ...InjectFunc) {
+	site := callerSite(1)
+	for i := range funcs {
+		validateRegistered(funcs[i])
+	}
+
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	for _, ifn := range funcs {
+		ifn.site = site
+		registry = append(registry, registered{ifn: ifn})
+	}
+}
+
+// RegisterByName registers ifn by name to the default registry,
+// it panics like Register or if name is duplicate or reserved name "private".
+func RegisterByName(name string, ifn InjectFunc) {
+	checkReservedName(name)
+	validateRegistered(ifn)
+	ifn.site = callerSite(1)
+
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	for i := range registry {
+		if registry[i].name == name {
+			panic(fmt.Errorf("duplicate registered function name: %s", name))
+		}
+	}
+	registry = append(registry, registered{name: name, ifn: ifn})
+}
+
+func validateRegistered(ifn InjectFunc) {
+	ifn.validate()
+	if ifn.Receiver != nil {
+		panic(fmt.Errorf("register func %v error: receiver is shared by containers", ifn))
+	}
+}
+
+// ProvideRegistered provide functions of the default registry in registering order,
+// whose labels are selected by labelSelector, all are provided if labelSelector is nil.
+// Provided functions keep their labels, so Populate selects them by its own label selector too.
+// It panics like ProvideFunc and ProvideFuncByName, all functions are checked before any is provided.
+func (c *Container) ProvideRegistered(labelSelector FuncLabelSelector) {
+	registryLock.Lock()
+	funcs := make([]registered, 0, len(registry))
+	for _, r := range registry {
+		if isLabelSelected(labelSelector, r.ifn.Label) {
+			funcs = append(funcs, r)
+		}
+	}
+	registryLock.Unlock()
+
+	c.lockRegistering()
+	defer c.mu.Unlock()
+	for _, r := range funcs {
+		if err := c.checkRegistered(r); err != nil {
+			panic(err)
+		}
+	}
+	for _, r := range funcs {
+		ifn := r.ifn.clone()
+		ifn.order = c.nextFuncOrder()
+		if r.name == "" {
+			c.unnamedFunctions = append(c.unnamedFunctions, ifn)
+			continue
+		}
+		c.namedFunctions[r.name] = ifn
+		c.namedFuncOrder = append(c.namedFuncOrder, r.name)
+	}
+}
+
+// checkRegistered return error if r can not be provided to c, c.mu should be locked.
+func (c *Container) checkRegistered(r registered) error {
+	if _, ok := c.namedFunctions[r.name]; ok && r.name != "" {
+		return fmt.Errorf("duplicate function name: %s", r.name)
+	}
+	if _, ok := c.namedValues[r.name]; ok && r.name != "" {
+		return fmt.Errorf("duplicate object name: %s", r.name)
+	}
+	return c.checkDecorated(r.name, r.ifn)
+}
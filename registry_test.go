@@ -0,0 +1,69 @@
+package injectgo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type registryClient struct {
+	Addr string
+}
+
+type registryService struct {
+	Client *registryClient `inject:""`
+	Cache  *registryClient `inject:"cache"`
+}
+
+func resetRegistry() {
+	registryLock.Lock()
+	defer registryLock.Unlock()
+	registry = nil
+}
+
+func TestProvideRegistered(t *testing.T) {
+	resetRegistry()
+	defer resetRegistry()
+
+	Register(InjectFunc{Fn: func() *registryClient { return &registryClient{Addr: "prod"} }, Label: "prod"},
+		InjectFunc{Fn: func() *registryClient { return &registryClient{Addr: "dev"} }, Label: "dev"})
+	RegisterByName("cache", InjectFunc{Fn: func() *registryClient { return &registryClient{Addr: "cache"} }})
+	assert.Panics(t, func() {
+		RegisterByName("cache", InjectFunc{Fn: func() *registryClient { return &registryClient{} }})
+	}, "should panic because name is duplicate")
+	assert.Panics(t, func() {
+		Register(InjectFunc{Fn: func(int) *registryClient { return &registryClient{} }})
+	}, "should panic because function is unsupported")
+	var receiver *registryClient
+	assert.Panics(t, func() {
+		Register(InjectFunc{Fn: func() *registryClient { return &registryClient{} }, Receiver: &receiver})
+	}, "should panic because receiver is set")
+	assert.Panics(t, func() {
+		RegisterByName("receiver", InjectFunc{Fn: func() *registryClient { return &registryClient{} }, Receiver: &receiver})
+	}, "should panic because receiver is set")
+
+	c := NewContainer()
+	s := &registryService{}
+	c.Provide(s)
+	c.ProvideRegistered(moduleSelector{"prod": true})
+	c.Populate(moduleSelector{"prod": true})
+	assert.Equal(t, "prod", s.Client.Addr)
+	assert.Equal(t, "cache", s.Cache.Addr)
+	assert.Len(t, c.Graph().Nodes, 3, "function with unselected label should not be provided")
+
+	c = NewContainer()
+	c.ProvideRegistered(nil)
+	assert.Len(t, c.Graph().Nodes, 3)
+
+	assert.Panics(t, func() {
+		RegisterByName(privateTag, InjectFunc{Fn: func() *registryClient { return &registryClient{} }})
+	}, "should panic because name is reserved")
+
+	/// test nothing is provided if any function can not be provided
+	c = NewContainer()
+	c.ProvideByName("cache", &registryClient{})
+	assert.Panics(t, func() {
+		c.ProvideRegistered(nil)
+	}, "should panic because name is duplicate")
+	assert.Len(t, c.Graph().Nodes, 1, "functions before the duplicate one should not be provided")
+}
@@ -0,0 +1,180 @@
+package injectgo
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Replace override object or function provided by name with obj.
+// Replaced binding keeps its label, module and privacy.
+// It returns error if obj is not pointer to struct or interface or nothing is provided by name.
+func (c *Container) Replace(name string, obj interface{}) error {
+	v := reflect.ValueOf(obj)
+	if !c.isStructPtrOrInterface(v) {
+		return fmt.Errorf("check obj: %v error: %v", obj, errValueNotPtrOrInterface)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.mutableError(); err != nil {
+		return fmt.Errorf("replace name %s error: %w", name, err)
+	}
+	if pv, ok := c.namedValues[name]; ok {
+		c.namedValues[name] = providedValue{value: v, label: pv.label, site: callerSite(1), module: pv.module, private: pv.private}
+		return nil
+	}
+	if ifn, ok := c.namedFunctions[name]; ok {
+		c.removeNamedFunction(name)
+		c.namedValues[name] = providedValue{value: v, label: ifn.Label, site: callerSite(1), module: ifn.module, private: ifn.private}
+		return nil
+	}
+	return fmt.Errorf("replace name %s error: %v", name, errNothingReplaced)
+}
+
+// ReplaceType override all unnamed objects and functions whose type is the same as obj's type.
+// Replaced binding keeps its label, module and privacy.
+// It returns error if obj is not pointer to struct or interface or nothing has the same type.
+func (c *Container) ReplaceType(obj interface{}) error {
+	v := reflect.ValueOf(obj)
+	if !c.isStructPtrOrInterface(v) {
+		return fmt.Errorf("check obj: %v error: %v", obj, errValueNotPtrOrInterface)
+	}
+	site := callerSite(1)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.mutableError(); err != nil {
+		return fmt.Errorf("replace type %v error: %w", v.Type(), err)
+	}
+	replaced := c.replaceUnnamedValues(v, site)
+	removed := c.removeUnnamedFunctions(v.Type())
+	if !replaced && len(removed) > 0 {
+		c.unnamedValues = append(c.unnamedValues, providedValue{value: v, label: removed[0].Label, site: site,
+			module: removed[0].module, private: removed[0].private})
+		replaced = true
+	}
+	if !replaced {
+		return fmt.Errorf("replace type %v error: %v", v.Type(), errNothingReplaced)
+	}
+	return nil
+}
+
+// ReplaceFunc override all unnamed objects and functions whose type is the same as ifn's return type.
+// Replaced binding keeps its label, module and privacy.
+// It panics like ProvideFunc if ifn is unsupported,
+// returns error if nothing has the same type.
+func (c *Container) ReplaceFunc(ifn InjectFunc) error {
+	ifn.validate()
+	ifn.site = callerSite(1)
+
+	tp := ifn.returnType()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.mutableError(); err != nil {
+		return fmt.Errorf("replace function type %v error: %w", tp, err)
+	}
+	if err := c.checkDecorated("", ifn); err != nil {
+		return fmt.Errorf("replace function type %v error: %v", tp, err)
+	}
+	removedValues := c.removeUnnamedValues(tp)
+	removedFuncs := c.removeUnnamedFunctions(tp)
+	if len(removedValues) == 0 && len(removedFuncs) == 0 {
+		return fmt.Errorf("replace function type %v error: %v", tp, errNothingReplaced)
+	}
+	if len(removedValues) > 0 {
+		ifn.Label, ifn.module, ifn.private = removedValues[0].label, removedValues[0].module, removedValues[0].private
+	} else {
+		ifn.Label, ifn.module, ifn.private = removedFuncs[0].Label, removedFuncs[0].module, removedFuncs[0].private
+	}
+	ifn.order = c.nextFuncOrder()
+	c.unnamedFunctions = append(c.unnamedFunctions, ifn)
+	return nil
+}
+
+// ReplaceFuncByName override object or function provided by name with ifn.
+// Replaced binding keeps its label, module and privacy.
+// It panics like ProvideFuncByName if ifn is unsupported,
+// returns error if nothing is provided by name.
+func (c *Container) ReplaceFuncByName(name string, ifn InjectFunc) error {
+	ifn.validate()
+	ifn.site = callerSite(1)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err := c.mutableError(); err != nil {
+		return fmt.Errorf("replace function name %s error: %w", name, err)
+	}
+	if err := c.checkDecorated(name, ifn); err != nil {
+		return fmt.Errorf("replace function name %s error: %v", name, err)
+	}
+	if pv, ok := c.namedValues[name]; ok {
+		delete(c.namedValues, name)
+		ifn.Label, ifn.module, ifn.private = pv.label, pv.module, pv.private
+		ifn.order = c.nextFuncOrder()
+		c.namedFunctions[name] = ifn
+		c.namedFuncOrder = append(c.namedFuncOrder, name)
+		return nil
+	}
+	if old, ok := c.namedFunctions[name]; ok {
+		ifn.Label, ifn.module, ifn.private = old.Label, old.module, old.private
+		ifn.order = old.order
+		c.namedFunctions[name] = ifn
+		return nil
+	}
+	return fmt.Errorf("replace function name %s error: %v", name, errNothingReplaced)
+}
+
+// replaceUnnamedValues replace unnamed objects of v's type in place keeping their label, module and privacy,
+// return true if any replaced.
+// Replaced objects are populated by next Populate.
+func (c *Container) replaceUnnamedValues(v reflect.Value, site string) bool {
+	replaced := false
+	for i := range c.unnamedValues {
+		if c.unnamedValues[i].value.Type() == v.Type() {
+			c.unnamedValues[i].value = v
+			c.unnamedValues[i].site = site
+			c.unnamedValues[i].populated = false
+			replaced = true
+		}
+	}
+	return replaced
+}
+
+func (c *Container) removeUnnamedValues(tp reflect.Type) []providedValue {
+	removed := make([]providedValue, 0)
+	kept := make([]providedValue, 0, len(c.unnamedValues))
+	for i := range c.unnamedValues {
+		if c.unnamedValues[i].value.Type() == tp {
+			removed = append(removed, c.unnamedValues[i])
+			continue
+		}
+		kept = append(kept, c.unnamedValues[i])
+	}
+	c.unnamedValues = kept
+	return removed
+}
+
+func (c *Container) removeUnnamedFunctions(tp reflect.Type) []InjectFunc {
+	removed := make([]InjectFunc, 0)
+	kept := make([]InjectFunc, 0, len(c.unnamedFunctions))
+	for i := range c.unnamedFunctions {
+		if c.unnamedFunctions[i].returnType() == tp {
+			removed = append(removed, c.unnamedFunctions[i])
+			continue
+		}
+		kept = append(kept, c.unnamedFunctions[i])
+	}
+	c.unnamedFunctions = kept
+	return removed
+}
+
+func (c *Container) removeNamedFunction(name string) {
+	delete(c.namedFunctions, name)
+	for i := range c.namedFuncOrder {
+		if c.namedFuncOrder[i] == name {
+			c.namedFuncOrder = append(c.namedFuncOrder[:i], c.namedFuncOrder[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package injectgo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type replaceRepo interface {
+	Find() string
+}
+
+type pgRepo struct{}
+
+func (r *pgRepo) Find() string {
+	return "pg"
+}
+
+type fakeRepo struct{}
+
+func (r *fakeRepo) Find() string {
+	return "fake"
+}
+
+type replaceClient struct {
+	Addr string
+}
+
+type replaceService struct {
+	Repo   replaceRepo    `inject:""`
+	Client *replaceClient `inject:""`
+	Named  *replaceClient `inject:"named"`
+}
+
+func provideReplaceWiring(c *Container) *replaceService {
+	s := &replaceService{}
+	c.Provide(s, &replaceClient{Addr: "prod"})
+	c.ProvideFunc(InjectFunc{
+		Fn: func() replaceRepo { return &pgRepo{} },
+	})
+	c.ProvideFuncByName("named", InjectFunc{
+		Fn: func() *replaceClient { return &replaceClient{Addr: "named prod"} },
+	})
+	return s
+}
+
+func TestContainer_Replace(t *testing.T) {
+	c := NewContainer()
+	s := provideReplaceWiring(c)
+
+	assert.NoError(t, c.ReplaceFunc(InjectFunc{
+		Fn: func() replaceRepo { return &fakeRepo{} },
+	}))
+	assert.NoError(t, c.ReplaceType(&replaceClient{Addr: "test"}))
+	assert.NoError(t, c.Replace("named", &replaceClient{Addr: "named test"}))
+	c.Populate(nil)
+
+	assert.Equal(t, "fake", s.Repo.Find())
+	assert.Equal(t, "test", s.Client.Addr)
+	assert.Equal(t, "named test", s.Named.Addr)
+
+	c = NewContainer()
+	s = provideReplaceWiring(c)
+	assert.NoError(t, c.ReplaceFuncByName("named", InjectFunc{
+		Fn: func() *replaceClient { return &replaceClient{Addr: "named func test"} },
+	}))
+	c.Populate(nil)
+	assert.Equal(t, "named func test", s.Named.Addr)
+	assert.Equal(t, "pg", s.Repo.Find())
+}
+
+func TestContainer_ReplaceNothing(t *testing.T) {
+	c := NewContainer()
+	provideReplaceWiring(c)
+
+	assert.Error(t, c.Replace("missing", &replaceClient{}))
+	assert.Error(t, c.ReplaceType(&fakeRepo{}))
+	assert.Error(t, c.ReplaceFunc(InjectFunc{
+		Fn: func() *fakeRepo { return &fakeRepo{} },
+	}))
+	err := c.ReplaceFuncByName("missing", InjectFunc{
+		Fn: func() *fakeRepo { return &fakeRepo{} },
+	})
+	assert.Error(t, err)
+	t.Log(err)
+
+	assert.Error(t, c.Replace("named", replaceClient{}), "should error because type not match")
+}
+
+func TestContainer_ReplaceFuncKeepsLabel(t *testing.T) {
+	s := &replaceService{}
+	c := NewContainer()
+	c.Provide(s)
+	c.ProvideWithLabel("prod", &replaceClient{Addr: "prod"})
+	c.ProvideFunc(InjectFunc{Fn: func() replaceRepo { return &pgRepo{} }, Label: "prod"})
+	c.ProvideFuncByName("named", InjectFunc{Fn: func() *replaceClient { return &replaceClient{Addr: "named"} }, Label: "prod"})
+
+	assert.NoError(t, c.ReplaceFunc(InjectFunc{Fn: func() replaceRepo { return &fakeRepo{} }}))
+	assert.NoError(t, c.ReplaceFunc(InjectFunc{Fn: func() *replaceClient { return &replaceClient{Addr: "fake"} }}))
+	assert.NoError(t, c.ReplaceFuncByName("named", InjectFunc{Fn: func() *replaceClient { return &replaceClient{Addr: "fake named"} }}))
+	for _, n := range c.Graph().Nodes {
+		if n.Kind == NodeFunction {
+			assert.Equal(t, "prod", n.Label, "replaced binding should keep its label")
+		}
+	}
+	c.Populate(labelSelector{labels: []string{"prod"}})
+	assert.Equal(t, "fake", s.Repo.Find())
+	assert.Equal(t, "fake", s.Client.Addr)
+	assert.Equal(t, "fake named", s.Named.Addr)
+}
+
+func TestContainer_ReplaceKeepsModule(t *testing.T) {
+	newModule := func() *Module {
+		return &Module{
+			Name:           "cache",
+			Objects:        []interface{}{&moduleCache{}},
+			PrivateObjects: []interface{}{&moduleClient{Addr: "cache"}},
+			PrivateNamed: map[string]interface{}{
+				"conn":   &moduleClient{Addr: "conn"},
+				"dialer": InjectFunc{Fn: func() *moduleClient { return &moduleClient{Addr: "dialer"} }},
+			},
+		}
+	}
+	checkPrivate := func(c *Container) {
+		private := 0
+		for _, n := range c.Graph().Nodes {
+			if n.Private {
+				private++
+				assert.Equal(t, "cache", n.Module, "replaced binding should keep its module")
+			}
+		}
+		assert.Equal(t, 3, private, "replaced binding should stay private")
+	}
+
+	c := NewContainer()
+	c.Install(newModule())
+	assert.NoError(t, c.ReplaceType(&moduleClient{Addr: "fake"}))
+	assert.NoError(t, c.Replace("conn", &moduleClient{Addr: "fake conn"}))
+	assert.NoError(t, c.ReplaceFuncByName("dialer", InjectFunc{Fn: func() *moduleClient { return &moduleClient{} }}))
+	checkPrivate(c)
+	c.Populate(nil)
+	obj, ok := c.LookupType((*moduleCache)(nil))
+	assert.True(t, ok)
+	assert.Equal(t, "fake", obj.(*moduleCache).Client.Addr)
+
+	c = NewContainer()
+	c.Install(newModule())
+	assert.NoError(t, c.ReplaceFunc(InjectFunc{Fn: func() *moduleClient { return &moduleClient{Addr: "fake"} }}))
+	assert.NoError(t, c.Replace("dialer", &moduleClient{Addr: "fake dialer"}))
+	assert.NoError(t, c.ReplaceFuncByName("conn", InjectFunc{Fn: func() *moduleClient { return &moduleClient{} }}))
+	checkPrivate(c)
+	c.ProvideByName("other", &moduleCache{})
+	err := recoverError(func() {
+		c.Populate(nil)
+	})
+	assert.EqualError(t, err, "private binding injected into other module: "+
+		"field Client of *injectgo.moduleCache (no module) is private *injectgo.moduleClient of module cache")
+}
@@ -0,0 +1,71 @@
+package injectgo
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// NewScope return a child container of c for a request or other unit of work.
+// Objects populated in c fulfill inject fields of objects provided to the child,
+// functions of c with ScopeRequest are called once per child when it is populated,
+// transient functions of c create objects of the child, and decorators and fallbacks of c apply to it.
+// Child is closed by its own Close, which closes objects created by it only.
+// It panics if c is not populated, c should not be populated again while its children are used.
+func (c *Container) NewScope() *Container {
+	c.rlockPopulated()
+	defer c.mu.RUnlock()
+	if !c.populated {
+		panic(fmt.Errorf("new scope error: %v", errNotPopulated))
+	}
+
+	s := NewContainer()
+	s.parent = c
+	s.graph.parent = c.graph
+	for _, t := range c.requestFunctions {
+		ifn := t.ifn.clone()
+		ifn.Scope = ScopeSingleton
+		if t.name == "" {
+			s.unnamedFunctions = append(s.unnamedFunctions, ifn)
+			continue
+		}
+		s.namedFunctions[t.name] = ifn
+		s.namedFuncOrder = append(s.namedFuncOrder, t.name)
+	}
+	s.decorators = append(s.decorators, c.decorators...)
+	s.fallbacks = append(s.fallbacks, c.fallbacks...)
+	return s
+}
+
+// eachParentBinding call fn with name and object of every object populated in ancestors of c,
+// and zero value of return type of their transient functions.
+func (c *Container) eachParentBinding(fn func(name string, v reflect.Value)) {
+	for p := c.parent; p != nil; p = p.parent {
+		p.mu.RLock()
+		for _, o := range p.selectedObjects[:p.populatedObjects] {
+			if !o.inner {
+				fn(o.name, o.value)
+			}
+		}
+		for name, t := range p.graph.namedTransients {
+			fn(name, reflect.Zero(t.ifn.returnType()))
+		}
+		for tp := range p.graph.unnamedTransients {
+			fn("", reflect.Zero(tp))
+		}
+		p.mu.RUnlock()
+	}
+}
+
+type scopeKey struct{}
+
+// WithScope return a copy of ctx carrying container c, usually a child created by NewScope.
+func WithScope(ctx context.Context, c *Container) context.Context {
+	return context.WithValue(ctx, scopeKey{}, c)
+}
+
+// FromContext return container carried by ctx, false if ctx carries no container.
+func FromContext(ctx context.Context) (*Container, bool) {
+	c, ok := ctx.Value(scopeKey{}).(*Container)
+	return c, ok
+}
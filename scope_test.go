@@ -0,0 +1,101 @@
+package injectgo
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type scopeConfig struct {
+	Name string
+}
+
+type scopeSession struct {
+	Config *scopeConfig `inject:""`
+	closes int
+}
+
+func (s *scopeSession) Close() error {
+	s.closes++
+	return nil
+}
+
+type scopeHandler struct {
+	Config  *scopeConfig      `inject:""`
+	Session *scopeSession     `inject:""`
+	Limiter *transientLimiter `inject:""`
+}
+
+func TestNewScope(t *testing.T) {
+	c := NewContainer()
+	cfg := &scopeConfig{Name: "app"}
+	calls := 0
+	c.Provide(cfg, &transientConfig{})
+	c.ProvideFunc(InjectFunc{
+		Fn: func() *scopeSession {
+			calls++
+			return &scopeSession{}
+		},
+		Scope: ScopeRequest,
+	})
+	c.ProvideFunc(InjectFunc{Fn: func() *transientLimiter { return &transientLimiter{} }, Scope: ScopeTransient})
+	assert.Panics(t, func() {
+		c.NewScope()
+	}, "should panic because container is not populated")
+	c.Populate(nil)
+	assert.Equal(t, 0, calls, "request scoped function should not be called by parent")
+	_, ok := c.LookupType((*scopeSession)(nil))
+	assert.False(t, ok)
+
+	handlers := make([]*scopeHandler, 2)
+	for i := range handlers {
+		s := c.NewScope()
+		h := &scopeHandler{}
+		s.Provide(h)
+		s.Populate(nil)
+		handlers[i] = h
+
+		assert.Equal(t, i+1, calls, "request scoped function should be called once per scope")
+		assert.Equal(t, cfg, h.Config)
+		assert.Equal(t, cfg, h.Session.Config)
+		assert.NotNil(t, h.Limiter)
+		assert.Equal(t, 1, h.Limiter.inits)
+		s.Close()
+		assert.Equal(t, 1, h.Session.closes)
+		assert.Equal(t, 1, h.Limiter.closes)
+	}
+	assert.True(t, handlers[0].Session != handlers[1].Session, "each scope should get its own object")
+	assert.True(t, handlers[0].Limiter != handlers[1].Limiter, "each scope should get its own object")
+
+	c.Close()
+	assert.Equal(t, 1, handlers[0].Session.closes, "parent should not close objects of scope")
+}
+
+func TestNewScope_Unfulfilled(t *testing.T) {
+	c := NewContainer()
+	c.Provide(&scopeConfig{}, &scopeHandler{})
+	c.ProvideFunc(InjectFunc{Fn: func() *scopeSession { return &scopeSession{} }, Scope: ScopeRequest})
+	assert.Panics(t, func() {
+		c.Populate(nil)
+	}, "should panic because singleton depends on request scoped object")
+
+	assert.Panics(t, func() {
+		var s *scopeSession
+		c := NewContainer()
+		c.ProvideFunc(InjectFunc{Fn: func() *scopeSession { return s }, Scope: ScopeRequest, Receiver: &s})
+	}, "should panic because request scoped function set receiver")
+}
+
+func TestScopeContext(t *testing.T) {
+	_, ok := FromContext(context.Background())
+	assert.False(t, ok)
+
+	c := NewContainer()
+	c.Populate(nil)
+	s := c.NewScope()
+	ctx := WithScope(context.Background(), s)
+	got, ok := FromContext(ctx)
+	assert.True(t, ok)
+	assert.Equal(t, s, got)
+}
@@ -0,0 +1,75 @@
+package injectgo
+
+import "fmt"
+
+// State is the lifecycle state of container.
+type State int
+
+const (
+	StateRegistering State = iota // objects and functions can be provided
+	StatePopulating               // Populate is running
+	StatePopulated                // Populate succeeded
+	StateClosing                  // Close is running
+	StateClosed                   // Close finished
+)
+
+func (s State) String() string {
+	switch s {
+	case StateRegistering:
+		return "registering"
+	case StatePopulating:
+		return "populating"
+	case StatePopulated:
+		return "populated"
+	case StateClosing:
+		return "closing"
+	case StateClosed:
+		return "closed"
+	default:
+		return fmt.Sprintf("State(%d)", int(s))
+	}
+}
+
+// State return current lifecycle state of c.
+func (c *Container) State() State {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.state
+}
+
+// Freeze forbid c to be mutated, Provide, ProvideFunc, Decorate and their variants panic with ErrFrozen,
+// Replace and its variants return ErrFrozen. Container is frozen after Populate succeeds.
+func (c *Container) Freeze() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.frozen = true
+}
+
+// Unfreeze allow c to be mutated again, eg. provide objects to populate incrementally.
+// It has no effect if c is closing or closed.
+func (c *Container) Unfreeze() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.state == StateClosing || c.state == StateClosed {
+		return
+	}
+	c.frozen = false
+}
+
+// Frozen return true if c is frozen.
+func (c *Container) Frozen() bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.frozen
+}
+
+// mutableError return error if bindings of c can not be mutated, should be called with c.mu locked.
+func (c *Container) mutableError() error {
+	if c.state == StatePopulating {
+		return errPopulating
+	}
+	if c.frozen {
+		return ErrFrozen
+	}
+	return nil
+}
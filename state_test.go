@@ -0,0 +1,77 @@
+package injectgo
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stateClient struct {
+	c      *Container
+	states []State
+}
+
+func (s *stateClient) Init() error {
+	s.states = append(s.states, s.c.State())
+	return nil
+}
+
+func (s *stateClient) Close() error {
+	s.states = append(s.states, s.c.State())
+	return nil
+}
+
+func TestContainer_State(t *testing.T) {
+	c := NewContainer()
+	cli := &stateClient{c: c}
+	assert.Equal(t, StateRegistering, c.State())
+	assert.False(t, c.Frozen())
+
+	c.Provide(cli)
+	c.Populate(nil)
+	assert.Equal(t, StatePopulated, c.State())
+	assert.True(t, c.Frozen())
+
+	panicErr := func(fn func()) (err error) {
+		defer func() {
+			err, _ = recover().(error)
+		}()
+		fn()
+		return nil
+	}
+	err := panicErr(func() {
+		c.Provide(&Person{})
+	})
+	assert.True(t, errors.Is(err, ErrFrozen), "%v", err)
+	err = panicErr(func() {
+		c.ProvideFuncByName("person", InjectFunc{Fn: func() fmt.Stringer { return &Person{} }})
+	})
+	assert.True(t, errors.Is(err, ErrFrozen), "%v", err)
+	err = panicErr(func() {
+		c.Decorate((*fmt.Stringer)(nil), func(s fmt.Stringer) fmt.Stringer { return s })
+	})
+	assert.True(t, errors.Is(err, ErrFrozen), "%v", err)
+	assert.True(t, errors.Is(c.ReplaceType(&stateClient{}), ErrFrozen))
+
+	c.Unfreeze()
+	replaced := &stateClient{c: c}
+	assert.NoError(t, c.ReplaceType(replaced))
+	c.Populate(nil)
+	assert.Equal(t, []State{StatePopulating}, replaced.states, "replaced object should be populated")
+	c.Freeze()
+	assert.True(t, errors.Is(c.Replace("unknown", &Person{}), ErrFrozen))
+
+	c.Close()
+	assert.Equal(t, StateClosed, c.State())
+	assert.Equal(t, []State{StatePopulating, StateClosing}, cli.states)
+	assert.Equal(t, []State{StatePopulating, StateClosing}, replaced.states)
+	c.Unfreeze()
+	assert.True(t, c.Frozen(), "closed container should keep frozen")
+	assert.Panics(t, func() {
+		c.Populate(nil)
+	}, "should panic because container is closed")
+
+	assert.Equal(t, "populated", StatePopulated.String())
+}
@@ -0,0 +1,150 @@
+package injectgo
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Scope decides how many objects a function creates.
+type Scope int
+
+const (
+	ScopeSingleton Scope = iota // function is called once, its object is shared by all inject fields
+	ScopeTransient              // function is called once per inject field, each field gets its own object
+	ScopeRequest                // function is called once per child container created by NewScope
+)
+
+func (s Scope) String() string {
+	switch s {
+	case ScopeSingleton:
+		return "singleton"
+	case ScopeTransient:
+		return "transient"
+	case ScopeRequest:
+		return "request"
+	default:
+		return fmt.Sprintf("Scope(%d)", int(s))
+	}
+}
+
+// privateTag like `inject:"private"` inject a new object of the field type only used by the field,
+// created by transient function of the type if provided, otherwise a new zero struct.
+const privateTag = "private"
+
+// checkReservedName panics if name is reserved by inject tag, objects provided by it are never injected.
+func checkReservedName(name string) {
+	if name == privateTag {
+		panic(fmt.Errorf("name %s is reserved", name))
+	}
+}
+
+// transient is a selected transient function.
+type transient struct {
+	name string
+	ifn  InjectFunc
+}
+
+// transientObject is an object created for an inject field by transient function,
+// or for a private field if from is nil.
+type transientObject struct {
+	value reflect.Value
+	from  *transient
+}
+
+// selectTransient check return type of transient function like a selected object,
+// the function is called when populating inject fields.
+func (c *Container) selectTransient(name string, ifn InjectFunc) {
+	tp := ifn.returnType()
+	if name == "" {
+		c.checker.popFulfilledUnnamedValues(reflect.Zero(tp))
+	} else {
+		c.checker.popFulfilledNamedValues(name, reflect.Zero(tp))
+	}
+	// object returned as interface is not injected, like singleton
+	if tp.Kind() == reflect.Ptr && tp.Elem().Kind() == reflect.Struct {
+		proto := reflect.New(tp.Elem())
+		c.checker.pushInjectedFields(proto)
+		c.detector.AddDetectObject(proto)
+	}
+	c.selectedTransients = append(c.selectedTransients, &transient{name: name, ifn: ifn})
+}
+
+// recordTransientObjects record objects created by transient functions and for private fields in last populating.
+func (c *Container) recordTransientObjects() {
+	for _, o := range c.graph.takeTransientObjects() {
+		if o.from == nil {
+			c.recordSelected(o.value, binding{kind: NodeObject}, false, -1)
+			continue
+		}
+		c.recordSelected(o.value, binding{name: o.from.name, label: o.from.ifn.Label, site: o.from.ifn.site, module: o.from.ifn.module, private: o.from.ifn.private, kind: NodeFunction}, false, -1)
+	}
+}
+
+// ProvideTransient add transient function t, which fulfills fields after provided objects.
+func (g *objectGraph) ProvideTransient(t *transient) {
+	if t.name != "" {
+		g.namedTransients[t.name] = t
+		return
+	}
+	g.unnamedTransients[t.ifn.returnType()] = t
+}
+
+// findTransient return transient function of g or its parent matching field.
+// It panics if several unnamed transient functions return types assignable to the field type.
+func (g *objectGraph) findTransient(field *injectField) *transient {
+	if field.tagName != "" && field.tagName != privateTag {
+		if t, ok := g.namedTransients[field.tagName]; ok {
+			return t
+		}
+	} else {
+		if t, ok := g.unnamedTransients[field.fieldType]; ok {
+			return t
+		}
+		var found *transient
+		for tp, t := range g.unnamedTransients {
+			if !tp.AssignableTo(field.fieldType) {
+				continue
+			}
+			if found != nil {
+				panic(fmt.Errorf("ambiguous transient functions of %v: %v and %v",
+					field.fieldType, found.ifn.returnType(), tp))
+			}
+			found = t
+		}
+		if found != nil {
+			return found
+		}
+	}
+	if g.parent != nil {
+		return g.parent.findTransient(field)
+	}
+	return nil
+}
+
+// newTransientObject call transient function and return its object to be populated.
+func (g *objectGraph) newTransientObject(t *transient) *injectObject {
+	v, err := t.ifn.create()
+	if err != nil {
+		panic(fmt.Errorf("transient function %s(%v) error: %v", t.name, t.ifn.returnType(), err))
+	}
+	g.transientObjects = append(g.transientObjects, transientObject{value: v, from: t})
+	return newInjectObject(v)
+}
+
+// newPrivateObject return a new zero struct to be populated for private field.
+func (g *objectGraph) newPrivateObject(field *injectField) *injectObject {
+	tp := field.fieldType
+	if tp.Kind() != reflect.Ptr || tp.Elem().Kind() != reflect.Struct {
+		panic(fmt.Errorf("private field %s should be pointer to struct", field))
+	}
+	v := reflect.New(tp.Elem())
+	g.transientObjects = append(g.transientObjects, transientObject{value: v})
+	return newInjectObject(v)
+}
+
+// takeTransientObjects return objects created since last call.
+func (g *objectGraph) takeTransientObjects() []transientObject {
+	objs := g.transientObjects
+	g.transientObjects = nil
+	return objs
+}
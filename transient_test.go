@@ -0,0 +1,188 @@
+package injectgo
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type transientConfig struct {
+	Rate int
+}
+
+type transientLimiter struct {
+	Config *transientConfig `inject:""`
+	inits  int
+	closes int
+}
+
+func (l *transientLimiter) Init() error {
+	l.inits++
+	return nil
+}
+
+func (l *transientLimiter) Close() error {
+	l.closes++
+	return nil
+}
+
+type transientComponent struct {
+	Limiter *transientLimiter `inject:""`
+	Named   *transientLimiter `inject:"limiter"`
+	Private *transientLimiter `inject:"private"`
+}
+
+func TestTransient(t *testing.T) {
+	c := NewContainer()
+	cfg := &transientConfig{Rate: 10}
+	a, b := &transientComponent{}, &transientComponent{}
+	calls := 0
+	newLimiter := func() *transientLimiter {
+		calls++
+		return &transientLimiter{}
+	}
+	c.Provide(cfg, a)
+	c.ProvideByName("b", b)
+	c.ProvideFunc(InjectFunc{Fn: newLimiter, Scope: ScopeTransient})
+	c.ProvideFuncByName("limiter", InjectFunc{Fn: newLimiter, Scope: ScopeTransient})
+	c.Populate(nil)
+
+	assert.Equal(t, 6, calls, "function should be called once per inject field")
+	limiters := []*transientLimiter{a.Limiter, a.Named, a.Private, b.Limiter, b.Named, b.Private}
+	seen := make(map[*transientLimiter]bool)
+	for _, l := range limiters {
+		assert.False(t, seen[l], "each field should get its own object")
+		seen[l] = true
+		assert.Equal(t, cfg, l.Config)
+		assert.Equal(t, 1, l.inits)
+	}
+	_, ok := c.Lookup("limiter")
+	assert.False(t, ok)
+	assert.Len(t, c.Graph().Nodes, 9)
+
+	c.Close()
+	for _, l := range limiters {
+		assert.Equal(t, 1, l.closes)
+	}
+
+	assert.Panics(t, func() {
+		var l *transientLimiter
+		c := NewContainer()
+		c.ProvideFunc(InjectFunc{Fn: newLimiter, Scope: ScopeTransient, Receiver: &l})
+	}, "should panic because transient function set receiver")
+
+	/// test several assignable transient functions are ambiguous
+	type stringerUser struct {
+		Stringer fmt.Stringer `inject:""`
+	}
+	c = NewContainer()
+	c.Provide(&stringerUser{})
+	c.ProvideFunc(InjectFunc{Fn: func() *Person { return &Person{} }, Scope: ScopeTransient})
+	c.ProvideFunc(InjectFunc{Fn: func() *transientName { return &transientName{} }, Scope: ScopeTransient})
+	assert.Panics(t, func() {
+		c.Populate(nil)
+	}, "should panic because transient function of field is ambiguous")
+}
+
+type transientName struct{}
+
+func (transientName) String() string {
+	return "name"
+}
+
+func TestPrivate(t *testing.T) {
+	c := NewContainer()
+	cfg := &transientConfig{}
+	shared := &transientLimiter{}
+	a, b := &transientComponent{}, &transientComponent{}
+	c.Provide(cfg, shared, a)
+	c.ProvideByName("b", b)
+	c.ProvideByName("limiter", shared)
+	c.Populate(nil)
+
+	assert.Equal(t, shared, a.Limiter)
+	assert.Equal(t, shared, b.Named)
+	assert.NotEqual(t, fmt.Sprintf("%p", a.Private), fmt.Sprintf("%p", b.Private))
+	assert.NotEqual(t, fmt.Sprintf("%p", shared), fmt.Sprintf("%p", a.Private))
+	assert.Equal(t, cfg, a.Private.Config)
+	assert.Equal(t, 1, a.Private.inits)
+
+	/// test fields of private object are checked
+	c = NewContainer()
+	c.Provide(&transientComponent{}, shared)
+	c.ProvideByName("limiter", shared)
+	assert.Panics(t, func() {
+		c.Populate(nil)
+	}, "should panic because config of private limiter is unfulfilled")
+
+	type privateInterface struct {
+		Stringer fmt.Stringer `inject:"private"`
+	}
+	c = NewContainer()
+	c.Provide(&privateInterface{})
+	assert.Panics(t, func() {
+		c.Populate(nil)
+	}, "should panic because private field is not pointer to struct")
+
+	assert.Panics(t, func() {
+		NewContainer().ProvideByName(privateTag, &transientConfig{})
+	}, "should panic because name is reserved")
+	assert.Panics(t, func() {
+		NewContainer().ProvideFuncByName(privateTag, InjectFunc{Fn: newTransientConfig})
+	}, "should panic because name is reserved")
+}
+
+func newTransientConfig() *transientConfig {
+	return &transientConfig{}
+}
+
+type transientCyclic struct {
+	Self *transientCyclic `inject:"private"`
+}
+
+func TestPrivate_Cyclic(t *testing.T) {
+	c := NewContainer()
+	c.Provide(&transientCyclic{})
+	assert.Panics(t, func() {
+		c.Populate(nil)
+	}, "should panic because private field depends on itself")
+}
+
+func TestTransient_Decorate(t *testing.T) {
+	decorateLimiter := func(l *transientLimiter) *transientLimiter {
+		return l
+	}
+	newTransient := func() InjectFunc {
+		return InjectFunc{Fn: func() *transientLimiter { return &transientLimiter{} }, Scope: ScopeTransient}
+	}
+
+	assert.Panics(t, func() {
+		c := NewContainer()
+		c.ProvideFunc(newTransient())
+		c.Decorate((*transientLimiter)(nil), decorateLimiter)
+	}, "should panic because decorator matches transient function")
+	assert.Panics(t, func() {
+		c := NewContainer()
+		c.ProvideFuncByName("limiter", newTransient())
+		c.DecorateByName("limiter", decorateLimiter)
+	}, "should panic because decorator matches transient function")
+	assert.Panics(t, func() {
+		c := NewContainer()
+		c.Decorate((*transientLimiter)(nil), decorateLimiter)
+		c.ProvideFunc(newTransient())
+	}, "should panic because transient function matches decorator")
+
+	c := NewContainer()
+	c.Decorate((*transientLimiter)(nil), decorateLimiter)
+	c.Provide(&transientLimiter{})
+	assert.Error(t, c.ReplaceFunc(newTransient()))
+
+	/// decorator of other name or type does not conflict
+	c = NewContainer()
+	c.DecorateByName("other", decorateLimiter)
+	c.Decorate((*transientConfig)(nil), func(cfg *transientConfig) *transientConfig { return cfg })
+	c.ProvideFuncByName("limiter", newTransient())
+	c.Provide(&transientConfig{})
+	c.Populate(nil)
+}